@@ -44,6 +44,10 @@ type ClusterObjectSetSpec struct {
 	// Previous revisions of the ClusterObjectSet to adopt objects from.
 	Previous []PreviousRevisionReference `json:"previous,omitempty"`
 
+	// Selects pre-existing objects without a controller, that the ClusterObjectSet takes ownership of.
+	// Allows a first revision to adopt objects created outside of Package Operator, e.g. by kubectl or CI.
+	AdoptObjects *metav1.LabelSelector `json:"adoptObjects,omitempty"`
+
 	ObjectSetTemplateSpec `json:",inline"`
 }
 
@@ -51,12 +55,39 @@ type ClusterObjectSetSpec struct {
 type ClusterObjectSetStatus struct {
 	// Conditions is a list of status conditions ths object is in.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// This field is not part of any API contract
 	// it will go away as soon as kubectl can print conditions!
 	// When evaluating object state in code, use .Conditions instead.
 	Phase ObjectSetStatusPhase `json:"phase,omitempty"`
 	// Computed revision number, monotonically increasing.
 	Revision int64 `json:"revision,omitempty"`
+	// Values collected from objects as configured in .spec.statusCollection.
+	// Non-string values are JSON encoded.
+	CollectedStatus map[string]string `json:"collectedStatus,omitempty"`
+	// Phase currently torn down, while objects are cleaned up during deletion or archival.
+	Teardown *ObjectSetTeardownStatus `json:"teardown,omitempty"`
+	// Objects left behind during teardown,
+	// because of their "package-operator.run/teardown-policy: Keep" annotation.
+	OrphanedObjects []ObjectSetObjectReference `json:"orphanedObjects,omitempty"`
+	// Objects stuck in deletion for a long time, while blocking teardown.
+	StuckObjects []ObjectSetStuckObject `json:"stuckObjects,omitempty"`
+	// Objects added, updated or removed compared to the previous revision.
+	// Recorded once, when the revision number is determined.
+	// Not recorded for the first revision and limited to the first 50 changes.
+	Changes []ObjectSetChange `json:"changes,omitempty"`
+	// Objects that could not be adopted during the last reconciliation,
+	// because they are owned by someone else.
+	Conflicts []ObjectSetConflict `json:"conflicts,omitempty"`
+	// Pre-existing objects matching .spec.adoptObjects, that are going to be adopted.
+	// Also reported while paused, so adoptions can be reviewed before they happen.
+	// Not reported anymore after the first successful rollout, when every object is controlled by the ObjectSet.
+	AdoptionCandidates []ObjectSetObjectReference `json:"adoptionCandidates,omitempty"`
+	// Objects of local phases managed by the ObjectSet, updated whenever an object is applied.
+	// Objects of phases delegated to an ObjectSetPhase are reported in the status of the phase.
+	// Limited to the first 100 objects.
+	ManagedObjects []ObjectSetManagedObject `json:"managedObjects,omitempty"`
 }
 
 func init() {
@@ -30,6 +30,9 @@ type ObjectSetTemplateSpec struct {
 	// All probes need to succeed for a package to be considered Available.
 	// Failing probes will prevent the reconciliation of objects in later phases.
 	AvailabilityProbes []ObjectSetProbe `json:"availabilityProbes"`
+	// Status Collection copies fields of objects that are part of the ObjectSet
+	// into .status.collectedStatus, so they can be read without access to the objects themselves.
+	StatusCollection []ObjectSetStatusCollection `json:"statusCollection,omitempty"`
 }
 
 // ObjectSet reconcile phase.
@@ -42,6 +45,53 @@ type ObjectSetTemplatePhase struct {
 	Class string `json:"class,omitempty"`
 	// Objects belonging to this phase.
 	Objects []ObjectSetObject `json:"objects"`
+	// Objects managed outside of Package Operator, that have to exist and pass their probes,
+	// before objects of this phase are reconciled and later phases can progress.
+	ExternalObjects []ObjectSetExternalObjects `json:"externalObjects,omitempty"`
+	// Reconciles objects of this phase one after another,
+	// ordered by their "package-operator.run/weight" annotation, lowest first.
+	// Objects are only reconciled after all previous objects pass probes.
+	Ordered bool `json:"ordered,omitempty"`
+	// Consecutive phases with the same parallel group are reconciled concurrently.
+	// Later phases wait until all phases of the group pass probes.
+	ParallelGroup string `json:"parallelGroup,omitempty"`
+	// Maximum time to wait for objects of this phase to be gone during teardown,
+	// before continuing with the previous phase. Waits indefinitely, if unset.
+	TeardownTimeout *metav1.Duration `json:"teardownTimeout,omitempty"`
+	// Cluster to reconcile objects of this phase on,
+	// instead of the cluster the ObjectSet lives on.
+	// Requires a class, the controller handling the class connects to the target cluster.
+	TargetCluster *ObjectSetPhaseTargetCluster `json:"targetCluster,omitempty"`
+}
+
+// Cluster objects of a phase are reconciled on.
+type ObjectSetPhaseTargetCluster struct {
+	// Secret containing a kubeconfig of the target cluster.
+	KubeconfigSecretRef KubeconfigSecretReference `json:"kubeconfigSecretRef"`
+}
+
+// References a kubeconfig stored in a Secret.
+type KubeconfigSecretReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+	// Namespace of the Secret.
+	// Required for cluster-scoped objects,
+	// namespaced objects may only reference Secrets in their own namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Key of the kubeconfig within the Secret.
+	// Defaults to "kubeconfig".
+	Key string `json:"key,omitempty"`
+}
+
+// Default key of the kubeconfig in a Secret referenced by KubeconfigSecretReference.
+const DefaultKubeconfigSecretKey = "kubeconfig"
+
+// Progress of the object teardown of an ObjectSet.
+type ObjectSetTeardownStatus struct {
+	// Name of the phase blocking teardown.
+	Phase string `json:"phase"`
+	// Time teardown of the phase started.
+	Since metav1.Time `json:"since"`
 }
 
 // An object that is part of the phase of an ObjectSet.
@@ -65,6 +115,25 @@ const (
 	// Succeeded condition is only set once,
 	// after a ObjectSet became Available for the first time.
 	ObjectSetSucceeded = "Succeeded"
+	// RemoteClusterReachable is reported for phases reconciled on another cluster,
+	// indicating whether the API server of that cluster can be reached.
+	ObjectSetRemoteClusterReachable = "RemoteClusterReachable"
+	// InsufficientPermissions is True, when Package Operator lacks
+	// permissions to manage some of the objects of this revision.
+	// All missing permissions are listed in the condition message.
+	ObjectSetInsufficientPermissions = "InsufficientPermissions"
+	// PolicyViolation is True, when objects of this revision violate
+	// the tenant isolation policy and will not be reconciled.
+	ObjectSetPolicyViolation = "PolicyViolation"
+	// DriftDetected is reported while paused,
+	// indicating whether objects differ from their desired state.
+	ObjectSetDriftDetected = "DriftDetected"
+	// TeardownBlocked is True, when teardown refuses to delete a
+	// CustomResourceDefinition, because instances of it still exist.
+	ObjectSetTeardownBlocked = "TeardownBlocked"
+	// Upgradeable is False while a rollout is in progress or objects are unavailable,
+	// signaling external upgrade orchestrators to hold cluster or operator upgrades.
+	ObjectSetUpgradeable = "Upgradeable"
 )
 
 type ObjectSetStatusPhase string
@@ -86,6 +155,23 @@ const (
 	ObjectSetStatusPhaseArchived ObjectSetStatusPhase = "Archived"
 )
 
+// Selects objects managed outside of Package Operator, that a phase waits for.
+// At least one object has to match and all matching objects have to pass the probes.
+type ObjectSetExternalObjects struct {
+	// API version of the objects.
+	// +example=cert-manager.io/v1
+	APIVersion string `json:"apiVersion"`
+	// Kind of the objects.
+	// +example=Certificate
+	Kind string `json:"kind"`
+	// Selects objects by labels, within the namespace of the ObjectSet.
+	// Cluster-scoped owners select objects across all namespaces.
+	// +example={matchLabels: {app.kubernetes.io/name: example-operator}}
+	Selector metav1.LabelSelector `json:"selector"`
+	// Probes all selected objects have to pass.
+	Probes []Probe `json:"probes,omitempty"`
+}
+
 // ObjectSetProbe define how ObjectSets check their children for their status.
 type ObjectSetProbe struct {
 	// Probe configuration parameters.
@@ -141,6 +227,122 @@ type ProbeFieldsEqualSpec struct {
 	FieldB string `json:"fieldB"`
 }
 
+// Copies a field of an object that is part of the ObjectSet into .status.collectedStatus.
+type ObjectSetStatusCollection struct {
+	// Key under which the collected value is reported in .status.collectedStatus.
+	// +example=operatorVersion
+	Name string `json:"name"`
+	// Object to collect the field from.
+	// Only objects that are part of the ObjectSet can be referenced.
+	ObjectRef StatusCollectionObjectReference `json:"objectRef"`
+	// JSON Path of the field to collect, as supported by kubectl.
+	// Curly braces around the expression are optional.
+	// Multiple matches are reported as JSON array.
+	// +example=.status.version
+	FieldPath string `json:"fieldPath"`
+}
+
+// References an object that is part of an ObjectSet.
+type StatusCollectionObjectReference struct {
+	// Object Group.
+	// +example=apps
+	Group string `json:"group"`
+	// Object Kind.
+	// +example=Deployment
+	Kind string `json:"kind"`
+	// Object Name.
+	// +example=example-deployment
+	Name string `json:"name"`
+}
+
+// References an object that was part of an ObjectSet.
+type ObjectSetObjectReference struct {
+	// Object Group.
+	// +example=apps
+	Group string `json:"group"`
+	// Object Kind.
+	// +example=Deployment
+	Kind string `json:"kind"`
+	// Object Name.
+	// +example=example-deployment
+	Name string `json:"name"`
+	// Object Namespace.
+	// +example=example-namespace
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// An object stuck in deletion, blocking teardown.
+type ObjectSetStuckObject struct {
+	ObjectSetObjectReference `json:",inline"`
+	// Finalizers preventing deletion of the object.
+	// +example=[example.com/cleanup]
+	Finalizers []string `json:"finalizers"`
+	// Time deletion of the object was requested.
+	DeletionTimestamp metav1.Time `json:"deletionTimestamp"`
+}
+
+// An object managed by an ObjectSet.
+type ObjectSetManagedObject struct {
+	ObjectSetObjectReference `json:",inline"`
+	// Object Version.
+	// +example=v1
+	Version string `json:"version"`
+	// Hash of the desired state last applied to the object.
+	// Computed from the object as specified in the ObjectSet, so it changes with its serialization
+	// and does not reflect changes made to the object on the cluster.
+	Hash string `json:"hash"`
+	// True, if the object passed the availability probes when it was last applied.
+	Available bool `json:"available"`
+	// Reason the object failed the availability probes.
+	Message string `json:"message,omitempty"`
+}
+
+// An object that could not be adopted, because it is owned by someone else.
+type ObjectSetConflict struct {
+	ObjectSetObjectReference `json:",inline"`
+	// Kind and name of the controller currently owning the object.
+	// +example=ObjectSet/example-1
+	Controller string `json:"controller,omitempty"`
+	// Field managers of the object.
+	Managers []ObjectSetFieldManager `json:"managers,omitempty"`
+}
+
+// Field manager of a conflicting object, as recorded in its managedFields.
+type ObjectSetFieldManager struct {
+	// Name of the field manager.
+	// +example=kubectl-edit
+	Manager string `json:"manager"`
+	// Operation the manager last performed, Apply or Update.
+	// +example=Update
+	Operation string `json:"operation,omitempty"`
+	// Paths of fields owned by the manager.
+	// +example=[spec.replicas]
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Kind of change to an object, compared to the previous revision.
+type ObjectSetChangeAction string
+
+const (
+	// Object is new in this revision.
+	ObjectSetChangeActionAdded ObjectSetChangeAction = "Added"
+	// Object differs from the previous revision.
+	ObjectSetChangeActionUpdated ObjectSetChangeAction = "Updated"
+	// Object is no longer part of this revision.
+	ObjectSetChangeActionRemoved ObjectSetChangeAction = "Removed"
+)
+
+// Change to an object, compared to the previous revision.
+type ObjectSetChange struct {
+	ObjectSetObjectReference `json:",inline"`
+	// Kind of change.
+	// +example=Updated
+	Action ObjectSetChangeAction `json:"action"`
+	// Paths of changed fields, if the object was updated.
+	// +example=[spec.replicas]
+	Fields []string `json:"fields,omitempty"`
+}
+
 // References a previous revision of an ObjectSet, ClusterObjectSet, ObjectSetPhase or ClusterObjectSetPhase.
 type PreviousRevisionReference struct {
 	// Name of a previous revision.
@@ -4,6 +4,17 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 // ObjectSetPhase is an internal API, allowing an ObjectSet to delegate a single phase to another custom controller.
 // ObjectSets will create subordinate ObjectSetPhases when `.class` within the phase specification is set.
+//
+// Controllers handling a class must report the Available condition and .status.observedGeneration
+// and list the objects they control in .status.controlledObjects.
+// Takeover: objects are only taken over from the revisions referenced in .spec.previous,
+// no matter whether they are controlled by the previous ObjectSet itself
+// or by one of its phases, regardless of class.
+// Objects controlled by a later revision must never be taken over.
+// Handback: when archived or deleted, phases delete the objects they still control
+// and only release objects that a later revision has taken over.
+// A later revision reconciling the phase in-process takes objects back the same way.
+// Conformance can be verified with package-operator.run/package-operator/conformance.
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -51,6 +62,10 @@ type ObjectSetPhaseStatus struct {
 	// Conditions is a list of status conditions ths object is in.
 	// +example=[{type: "Available", status: "True"}]
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The most recent generation observed by the controller.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// References all objects controlled by this phase.
+	ControlledObjects []ObjectSetObjectReference `json:"controlledObjects,omitempty"`
 }
 
 func init() {
@@ -166,6 +166,11 @@ func (in *ClusterObjectSetPhaseStatus) DeepCopyInto(out *ClusterObjectSetPhaseSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ControlledObjects != nil {
+		in, out := &in.ControlledObjects, &out.ControlledObjects
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterObjectSetPhaseStatus.
@@ -186,6 +191,11 @@ func (in *ClusterObjectSetSpec) DeepCopyInto(out *ClusterObjectSetSpec) {
 		*out = make([]PreviousRevisionReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdoptObjects != nil {
+		in, out := &in.AdoptObjects, &out.AdoptObjects
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ObjectSetTemplateSpec.DeepCopyInto(&out.ObjectSetTemplateSpec)
 }
 
@@ -209,6 +219,54 @@ func (in *ClusterObjectSetStatus) DeepCopyInto(out *ClusterObjectSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CollectedStatus != nil {
+		in, out := &in.CollectedStatus, &out.CollectedStatus
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Teardown != nil {
+		in, out := &in.Teardown, &out.Teardown
+		*out = new(ObjectSetTeardownStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanedObjects != nil {
+		in, out := &in.OrphanedObjects, &out.OrphanedObjects
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckObjects != nil {
+		in, out := &in.StuckObjects, &out.StuckObjects
+		*out = make([]ObjectSetStuckObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ObjectSetChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]ObjectSetConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdoptionCandidates != nil {
+		in, out := &in.AdoptionCandidates, &out.AdoptionCandidates
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedObjects != nil {
+		in, out := &in.ManagedObjects, &out.ManagedObjects
+		*out = make([]ObjectSetManagedObject, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterObjectSetStatus.
@@ -221,6 +279,21 @@ func (in *ClusterObjectSetStatus) DeepCopy() *ClusterObjectSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigSecretReference) DeepCopyInto(out *KubeconfigSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigSecretReference.
+func (in *KubeconfigSecretReference) DeepCopy() *KubeconfigSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSet) DeepCopyInto(out *ObjectSet) {
 	*out = *in
@@ -248,6 +321,93 @@ func (in *ObjectSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetChange) DeepCopyInto(out *ObjectSetChange) {
+	*out = *in
+	out.ObjectSetObjectReference = in.ObjectSetObjectReference
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetChange.
+func (in *ObjectSetChange) DeepCopy() *ObjectSetChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetConflict) DeepCopyInto(out *ObjectSetConflict) {
+	*out = *in
+	out.ObjectSetObjectReference = in.ObjectSetObjectReference
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]ObjectSetFieldManager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetConflict.
+func (in *ObjectSetConflict) DeepCopy() *ObjectSetConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetExternalObjects) DeepCopyInto(out *ObjectSetExternalObjects) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]Probe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetExternalObjects.
+func (in *ObjectSetExternalObjects) DeepCopy() *ObjectSetExternalObjects {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetExternalObjects)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetFieldManager) DeepCopyInto(out *ObjectSetFieldManager) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetFieldManager.
+func (in *ObjectSetFieldManager) DeepCopy() *ObjectSetFieldManager {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetFieldManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetList) DeepCopyInto(out *ObjectSetList) {
 	*out = *in
@@ -280,6 +440,22 @@ func (in *ObjectSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetManagedObject) DeepCopyInto(out *ObjectSetManagedObject) {
+	*out = *in
+	out.ObjectSetObjectReference = in.ObjectSetObjectReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetManagedObject.
+func (in *ObjectSetManagedObject) DeepCopy() *ObjectSetManagedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetManagedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetObject) DeepCopyInto(out *ObjectSetObject) {
 	*out = *in
@@ -296,6 +472,21 @@ func (in *ObjectSetObject) DeepCopy() *ObjectSetObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetObjectReference) DeepCopyInto(out *ObjectSetObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetObjectReference.
+func (in *ObjectSetObjectReference) DeepCopy() *ObjectSetObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetPhase) DeepCopyInto(out *ObjectSetPhase) {
 	*out = *in
@@ -393,6 +584,11 @@ func (in *ObjectSetPhaseStatus) DeepCopyInto(out *ObjectSetPhaseStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ControlledObjects != nil {
+		in, out := &in.ControlledObjects, &out.ControlledObjects
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetPhaseStatus.
@@ -405,6 +601,22 @@ func (in *ObjectSetPhaseStatus) DeepCopy() *ObjectSetPhaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetPhaseTargetCluster) DeepCopyInto(out *ObjectSetPhaseTargetCluster) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetPhaseTargetCluster.
+func (in *ObjectSetPhaseTargetCluster) DeepCopy() *ObjectSetPhaseTargetCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetPhaseTargetCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetProbe) DeepCopyInto(out *ObjectSetProbe) {
 	*out = *in
@@ -436,6 +648,11 @@ func (in *ObjectSetSpec) DeepCopyInto(out *ObjectSetSpec) {
 		*out = make([]PreviousRevisionReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.AdoptObjects != nil {
+		in, out := &in.AdoptObjects, &out.AdoptObjects
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	in.ObjectSetTemplateSpec.DeepCopyInto(&out.ObjectSetTemplateSpec)
 }
 
@@ -459,6 +676,54 @@ func (in *ObjectSetStatus) DeepCopyInto(out *ObjectSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CollectedStatus != nil {
+		in, out := &in.CollectedStatus, &out.CollectedStatus
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Teardown != nil {
+		in, out := &in.Teardown, &out.Teardown
+		*out = new(ObjectSetTeardownStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OrphanedObjects != nil {
+		in, out := &in.OrphanedObjects, &out.OrphanedObjects
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckObjects != nil {
+		in, out := &in.StuckObjects, &out.StuckObjects
+		*out = make([]ObjectSetStuckObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]ObjectSetChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]ObjectSetConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdoptionCandidates != nil {
+		in, out := &in.AdoptionCandidates, &out.AdoptionCandidates
+		*out = make([]ObjectSetObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedObjects != nil {
+		in, out := &in.ManagedObjects, &out.ManagedObjects
+		*out = make([]ObjectSetManagedObject, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetStatus.
@@ -471,6 +736,60 @@ func (in *ObjectSetStatus) DeepCopy() *ObjectSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetStatusCollection) DeepCopyInto(out *ObjectSetStatusCollection) {
+	*out = *in
+	out.ObjectRef = in.ObjectRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetStatusCollection.
+func (in *ObjectSetStatusCollection) DeepCopy() *ObjectSetStatusCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetStatusCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetStuckObject) DeepCopyInto(out *ObjectSetStuckObject) {
+	*out = *in
+	out.ObjectSetObjectReference = in.ObjectSetObjectReference
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.DeletionTimestamp.DeepCopyInto(&out.DeletionTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetStuckObject.
+func (in *ObjectSetStuckObject) DeepCopy() *ObjectSetStuckObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetStuckObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetTeardownStatus) DeepCopyInto(out *ObjectSetTeardownStatus) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetTeardownStatus.
+func (in *ObjectSetTeardownStatus) DeepCopy() *ObjectSetTeardownStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetTeardownStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetTemplatePhase) DeepCopyInto(out *ObjectSetTemplatePhase) {
 	*out = *in
@@ -481,6 +800,23 @@ func (in *ObjectSetTemplatePhase) DeepCopyInto(out *ObjectSetTemplatePhase) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ExternalObjects != nil {
+		in, out := &in.ExternalObjects, &out.ExternalObjects
+		*out = make([]ObjectSetExternalObjects, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TeardownTimeout != nil {
+		in, out := &in.TeardownTimeout, &out.TeardownTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TargetCluster != nil {
+		in, out := &in.TargetCluster, &out.TargetCluster
+		*out = new(ObjectSetPhaseTargetCluster)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetTemplatePhase.
@@ -510,6 +846,11 @@ func (in *ObjectSetTemplateSpec) DeepCopyInto(out *ObjectSetTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StatusCollection != nil {
+		in, out := &in.StatusCollection, &out.StatusCollection
+		*out = make([]ObjectSetStatusCollection, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetTemplateSpec.
@@ -631,3 +972,18 @@ func (in *ProbeSelector) DeepCopy() *ProbeSelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusCollectionObjectReference) DeepCopyInto(out *StatusCollectionObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusCollectionObjectReference.
+func (in *StatusCollectionObjectReference) DeepCopy() *StatusCollectionObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusCollectionObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,87 @@
+// The package client contains helpers for Go programs integrating with Package Operator,
+// built on top of the controller-runtime client.
+// It works with the typed objects of package-operator.run/apis,
+// so the given client needs a scheme that includes them, see apis.AddToScheme.
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// Default interval between checks while waiting for an object.
+const DefaultWaitInterval = time.Second
+
+// Client wraps a controller-runtime client with Package Operator specific helpers.
+type Client struct {
+	client       ctrlclient.Client
+	waitInterval time.Duration
+}
+
+// Returns a new Client wrapping the given controller-runtime client.
+func New(c ctrlclient.Client) *Client {
+	return &Client{
+		client:       c,
+		waitInterval: DefaultWaitInterval,
+	}
+}
+
+// Waits until the given ObjectSet, ClusterObjectSet, ObjectSetPhase or ClusterObjectSetPhase
+// reports the Available condition as True for its current generation.
+// obj is updated with the latest state from the cluster.
+// Use a context with deadline to limit the time waited.
+func (c *Client) WaitForAvailable(ctx context.Context, obj ctrlclient.Object) error {
+	return c.WaitForCondition(ctx, obj, corev1alpha1.ObjectSetAvailable, metav1.ConditionTrue)
+}
+
+// Waits until the given object reports a condition with the given status for its current generation.
+// obj is updated with the latest state from the cluster.
+func (c *Client) WaitForCondition(
+	ctx context.Context, obj ctrlclient.Object,
+	conditionType string, status metav1.ConditionStatus,
+) error {
+	key := ctrlclient.ObjectKeyFromObject(obj)
+	err := wait.PollImmediateUntilWithContext(ctx, c.waitInterval, func(ctx context.Context) (bool, error) {
+		if err := c.client.Get(ctx, key, obj); err != nil {
+			return false, err
+		}
+		conditions, err := Conditions(obj)
+		if err != nil {
+			return false, err
+		}
+		cond := meta.FindStatusCondition(conditions, conditionType)
+		return cond != nil && cond.Status == status &&
+			cond.ObservedGeneration == obj.GetGeneration(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for %s condition %s=%s: %w", key, conditionType, status, err)
+	}
+	return nil
+}
+
+// Sets the lifecycle state of the given ObjectSet or ClusterObjectSet.
+// obj is updated with the latest state from the cluster.
+func (c *Client) SetLifecycleState(
+	ctx context.Context, obj ctrlclient.Object, state corev1alpha1.ObjectSetLifecycleState,
+) error {
+	switch obj.(type) {
+	case *corev1alpha1.ObjectSet, *corev1alpha1.ClusterObjectSet:
+	default:
+		return fmt.Errorf("%w: %T has no lifecycle state", ErrUnsupportedObject, obj)
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"lifecycleState":%q}}`, state)
+	if err := c.client.Patch(ctx, obj, ctrlclient.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+		return fmt.Errorf("setting lifecycle state of %s: %w", ctrlclient.ObjectKeyFromObject(obj), err)
+	}
+	return nil
+}
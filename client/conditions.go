@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// ErrUnsupportedObject is returned for objects that are not Package Operator APIs
+// or don't support the requested operation.
+var ErrUnsupportedObject = errors.New("unsupported object")
+
+// Returns the status conditions of an ObjectSet, ClusterObjectSet,
+// ObjectSetPhase or ClusterObjectSetPhase.
+func Conditions(obj ctrlclient.Object) ([]metav1.Condition, error) {
+	switch o := obj.(type) {
+	case *corev1alpha1.ObjectSet:
+		return o.Status.Conditions, nil
+	case *corev1alpha1.ClusterObjectSet:
+		return o.Status.Conditions, nil
+	case *corev1alpha1.ObjectSetPhase:
+		return o.Status.Conditions, nil
+	case *corev1alpha1.ClusterObjectSetPhase:
+		return o.Status.Conditions, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedObject, obj)
+}
+
+// Returns true, if the object reports the Available condition as True for its current generation.
+func IsAvailable(obj ctrlclient.Object) bool {
+	return isConditionTrue(obj, corev1alpha1.ObjectSetAvailable)
+}
+
+// Returns true, if the object reports the Paused condition as True for its current generation.
+func IsPaused(obj ctrlclient.Object) bool {
+	return isConditionTrue(obj, corev1alpha1.ObjectSetPaused)
+}
+
+// Returns true, if the object reports the Archived condition as True.
+// Archived objects are not reconciled anymore, so the generation is not checked.
+func IsArchived(obj ctrlclient.Object) bool {
+	conditions, _ := Conditions(obj)
+	return meta.IsStatusConditionTrue(conditions, corev1alpha1.ObjectSetArchived)
+}
+
+func isConditionTrue(obj ctrlclient.Object, conditionType string) bool {
+	conditions, _ := Conditions(obj)
+	cond := meta.FindStatusCondition(conditions, conditionType)
+	return cond != nil && cond.Status == metav1.ConditionTrue &&
+		cond.ObservedGeneration == obj.GetGeneration()
+}
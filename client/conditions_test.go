@@ -0,0 +1,40 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func TestIsAvailable(t *testing.T) {
+	objectSet := &corev1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status: corev1alpha1.ObjectSetStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               corev1alpha1.ObjectSetAvailable,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+				},
+			},
+		},
+	}
+	if IsAvailable(objectSet) {
+		t.Error("expected condition of an outdated generation to be ignored")
+	}
+
+	objectSet.Status.Conditions[0].ObservedGeneration = 2
+	if !IsAvailable(objectSet) {
+		t.Error("expected ObjectSet to be available")
+	}
+}
+
+func TestConditions_unsupported(t *testing.T) {
+	_, err := Conditions(&metav1.PartialObjectMetadata{})
+	if !errors.Is(err, ErrUnsupportedObject) {
+		t.Errorf("expected ErrUnsupportedObject, got: %v", err)
+	}
+}
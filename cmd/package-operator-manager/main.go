@@ -2,51 +2,131 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"runtime/debug"
+	"time"
 
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfigv1alpha1 "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	pkoapis "package-operator.run/apis"
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/components"
 	"package-operator.run/package-operator/internal/controllers"
-	"package-operator.run/package-operator/internal/controllers/objectsets"
-	"package-operator.run/package-operator/internal/dynamiccache"
+	"package-operator.run/package-operator/internal/controllers/sharding"
+	"package-operator.run/package-operator/internal/inventory"
+	"package-operator.run/package-operator/internal/metrics"
 )
 
 type opts struct {
-	metricsAddr          string
-	pprofAddr            string
-	namespace            string
-	enableLeaderElection bool
-	probeAddr            string
-	printVersion         bool
+	metricsAddr             string
+	pprofAddr               string
+	inventoryAddr           string
+	namespace               string
+	enableLeaderElection    bool
+	leaseDuration           time.Duration
+	renewDeadline           time.Duration
+	retryPeriod             time.Duration
+	probeAddr               string
+	printVersion            bool
+	workQueueStallThreshold time.Duration
+	controllerConcurrency   controllers.GroupKindConcurrency
+	shards                  int
+	shard                   int
+	shardAssigner           bool
+	namespacedWatches       bool
+	tenantIsolation         bool
+	kindPolicy              controllers.KindPolicy
+	forceRemoveFinalizers   bool
+	applyQPS                float64
+	applyBurst              int
+	settleDelays            controllers.GroupKindDurations
+	applyParallelism        controllers.GroupKindLimits
+	applyRetries            controllers.GroupKindRetryPolicies
 }
 
 func main() {
-	var opts opts
+	opts := opts{
+		controllerConcurrency: controllers.GroupKindConcurrency{},
+		kindPolicy: controllers.KindPolicy{
+			Denied:  controllers.GroupKinds{},
+			Allowed: controllers.GroupKinds{},
+		},
+		settleDelays:     controllers.GroupKindDurations{},
+		applyParallelism: controllers.GroupKindLimits{},
+		applyRetries:     controllers.GroupKindRetryPolicies{},
+	}
 	flag.StringVar(&opts.metricsAddr, "metrics-addr", ":8080",
 		"The address the metric endpoint binds to.")
 	flag.StringVar(&opts.pprofAddr, "pprof-addr", "",
 		"The address the pprof web endpoint binds to.")
+	flag.StringVar(&opts.inventoryAddr, "inventory-addr", "",
+		"The address the read-only inventory endpoint binds to. "+
+			"Serves all ObjectSets and ClusterObjectSets of the whole cluster with their revision and health on /inventory. "+
+			"The endpoint is unauthenticated, only bind it to addresses trusted clients can reach.")
 	flag.StringVar(&opts.namespace, "namespace", os.Getenv("PKO_NAMESPACE"),
 		"The namespace the operator is deployed into.")
 	flag.BoolVar(&opts.enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&opts.leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Duration non-leader candidates wait before trying to acquire leadership.")
+	flag.DurationVar(&opts.renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Duration the leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&opts.retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Duration candidates wait between tries of acquiring or renewing leadership.")
 	flag.StringVar(&opts.probeAddr, "health-probe-bind-address", ":8081",
 		"The address the probe endpoint binds to.")
+	flag.DurationVar(&opts.workQueueStallThreshold, "work-queue-stall-threshold", 10*time.Minute,
+		"Liveness checks fail, when a controller is processing a single item for longer than this duration.")
+	flag.IntVar(&opts.shards, "shards", 0,
+		"Number of shards to split ObjectSets across. 0 disables sharding.")
+	flag.IntVar(&opts.shard, "shard", 0,
+		"Shard reconciled by this instance, when sharding is enabled.")
+	flag.BoolVar(&opts.shardAssigner, "shard-assigner", false,
+		"Only run the shard assigner, labeling objects with their shard.")
+	flag.BoolVar(&opts.namespacedWatches, "namespaced-watches", false,
+		"Watch objects only within the namespaces of the ObjectSets managing them, "+
+			"instead of cluster-wide. ClusterObjectSets still require cluster-wide watches.")
+	flag.BoolVar(&opts.tenantIsolation, "tenant-isolation", false,
+		"Restrict ObjectSets to namespaced objects within their own namespace. "+
+			"Can also be enabled per namespace with the "+controllers.TenantIsolationAnnotation+"=true annotation.")
+	flag.Var(opts.kindPolicy.Denied, "denied-kinds",
+		"Kinds of objects that must not be managed, e.g. \"ClusterRoleBinding.rbac.authorization.k8s.io,ValidatingWebhookConfiguration.admissionregistration.k8s.io\".")
+	flag.Var(opts.kindPolicy.Allowed, "allowed-kinds",
+		"If set, only objects of these kinds may be managed, e.g. \"Deployment.apps,ConfigMap\".")
+	flag.BoolVar(&opts.forceRemoveFinalizers, "force-remove-finalizers", false,
+		"Remove package-operator.run/ finalizers from objects stuck in deletion during teardown for longer than "+
+			controllers.StuckDeletionThreshold.String()+".")
+	flag.Float64Var(&opts.applyQPS, "apply-qps", 0,
+		"Maximum number of object writes per second across all controllers. 0 disables the limit.")
+	flag.IntVar(&opts.applyBurst, "apply-burst", 10,
+		"Maximum number of object writes allowed in a burst, when --apply-qps is set.")
+	flag.Var(opts.applyParallelism, "apply-parallelism",
+		"Maximum number of concurrent object writes per kind across all controllers, "+
+			"e.g. \"CustomResourceDefinition.apiextensions.k8s.io=1\". Other kinds are not limited.")
+	flag.Var(opts.applyRetries, "apply-retries",
+		"Retries of object writes failing with transient errors per kind, as RETRIESxBACKOFF with the backoff doubling "+
+			"after every retry, e.g. \"ValidatingWebhookConfiguration.admissionregistration.k8s.io=3x2s\". "+
+			"Writes of other kinds are retried by requeuing the reconcile.")
+	flag.Var(opts.settleDelays, "settle-delays",
+		"Time to wait after creating objects of these kinds, before reconciling further objects of the phase, "+
+			"e.g. \"CustomResourceDefinition.apiextensions.k8s.io=5s,ValidatingWebhookConfiguration.admissionregistration.k8s.io=10s\".")
+	flag.Var(opts.controllerConcurrency, "controller-concurrency",
+		"Maximum concurrent reconciles per controller, e.g. \"ObjectSet=10,ObjectSetPhase=5\". Defaults to 1.")
 	flag.BoolVar(&opts.printVersion, "version", false, "print version information and exit")
 	flag.Parse()
 
@@ -79,27 +159,57 @@ func main() {
 }
 
 func run(log logr.Logger, scheme *runtime.Scheme, opts opts) error {
+	leaderElectionID := "8a4hp84a6s.package-operator-lock"
+	var newCache cache.NewCacheFunc
+	switch {
+	case opts.shardAssigner:
+		if opts.shards < 1 {
+			return errors.New("--shard-assigner requires --shards")
+		}
+		leaderElectionID += "-shard-assigner"
+
+	case opts.shards > 0:
+		if opts.shard < 0 || opts.shard >= opts.shards {
+			return fmt.Errorf("--shard must be between 0 and %d", opts.shards-1)
+		}
+		leaderElectionID += fmt.Sprintf("-shard-%d", opts.shard)
+
+		// Only see objects assigned to this shard.
+		shardSelector := cache.ObjectSelector{Label: sharding.Selector(opts.shard)}
+		newCache = cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1alpha1.ObjectSet{}:             shardSelector,
+				&corev1alpha1.ClusterObjectSet{}:      shardSelector,
+				&corev1alpha1.ObjectSetPhase{}:        shardSelector,
+				&corev1alpha1.ClusterObjectSetPhase{}: shardSelector,
+			},
+		})
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                     scheme,
 		MetricsBindAddress:         opts.metricsAddr,
 		HealthProbeBindAddress:     opts.probeAddr,
+		LivenessEndpointName:       "/livez",
 		Port:                       9443,
 		LeaderElectionResourceLock: "leases",
 		LeaderElection:             opts.enableLeaderElection,
-		LeaderElectionID:           "8a4hp84a6s.package-operator-lock",
+		LeaderElectionID:           leaderElectionID,
+		// Release the lease when shutting down,
+		// so a standby replica can take over without waiting for the lease to expire.
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &opts.leaseDuration,
+		RenewDeadline:                 &opts.renewDeadline,
+		RetryPeriod:                   &opts.retryPeriod,
+		NewCache:                      newCache,
+		Controller: ctrlconfigv1alpha1.ControllerConfigurationSpec{
+			GroupKindConcurrency: opts.controllerConcurrency,
+		},
 	})
 	if err != nil {
 		return fmt.Errorf("creating manager: %w", err)
 	}
 
-	// Health and Ready checks
-	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
-		return fmt.Errorf("unable to set up health check: %w", err)
-	}
-	if err := mgr.AddReadyzCheck("check", healthz.Ping); err != nil {
-		return fmt.Errorf("unable to set up ready check: %w", err)
-	}
-
 	// PPROF
 	if len(opts.pprofAddr) > 0 {
 		mux := http.NewServeMux()
@@ -109,61 +219,117 @@ func run(log logr.Logger, scheme *runtime.Scheme, opts opts) error {
 		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
-		s := &http.Server{Addr: opts.pprofAddr, Handler: mux}
-		err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
-			errCh := make(chan error)
-			defer func() {
-				for range errCh {
-				} // drain errCh for GC
-			}()
-			go func() {
-				defer close(errCh)
-				errCh <- s.ListenAndServe()
-			}()
-
-			select {
-			case err := <-errCh:
-				return err
-			case <-ctx.Done():
-				s.Close()
-				return nil
-			}
-		}))
-		if err != nil {
+		if err := mgr.Add(newHTTPServerRunnable(
+			&http.Server{Addr: opts.pprofAddr, Handler: mux})); err != nil {
 			return fmt.Errorf("unable to create pprof server: %w", err)
 		}
 	}
 
-	// DynamicCache
-	dc := dynamiccache.NewCache(
-		mgr.GetConfig(), mgr.GetScheme(), mgr.GetRESTMapper(),
-		dynamiccache.SelectorsByGVK{
-			// Only cache objects with our label selector,
-			// so we prevent our caches from exploding!
-			schema.GroupVersionKind{}: dynamiccache.Selector{
-				Label: labels.SelectorFromSet(labels.Set{
-					controllers.DynamicCacheLabel: "True",
-				}),
-			},
-		})
+	// Inventory
+	// Lets UIs and inventory systems query all ObjectSets in a single call,
+	// without requiring list permissions on the ObjectSet APIs.
+	if len(opts.inventoryAddr) > 0 {
+		var reader client.Reader = mgr.GetClient()
+		if opts.shards > 0 {
+			// The cache only holds the objects of this shard.
+			reader = mgr.GetAPIReader()
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/inventory", inventory.NewHandler(
+			reader, ctrl.Log.WithName("inventory")))
+
+		if err := mgr.Add(newHTTPServerRunnable(
+			&http.Server{Addr: opts.inventoryAddr, Handler: mux})); err != nil {
+			return fmt.Errorf("unable to create inventory server: %w", err)
+		}
+	}
 
-	// ObjectSet
-	if err = (objectsets.NewObjectSetController(
-		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ObjectSet"),
-		mgr.GetScheme(), dc,
-	).SetupWithManager(mgr)); err != nil {
-		return fmt.Errorf("unable to create controller for ObjectSet: %w", err)
+	// Health and Ready checks
+	// Liveness is served on /livez and fails when controllers stop processing work.
+	// Readiness requires all caches to have synced their initial state.
+	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up health check: %w", err)
+	}
+	if err := mgr.AddHealthzCheck(
+		"workqueues", metrics.NewWorkQueueStallChecker(opts.workQueueStallThreshold).Check); err != nil {
+		return fmt.Errorf("unable to set up work queue health check: %w", err)
 	}
-	if err = (objectsets.NewClusterObjectSetController(
-		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterObjectSet"),
-		mgr.GetScheme(), dc,
-	).SetupWithManager(mgr)); err != nil {
-		return fmt.Errorf("unable to create controller for ClusterObjectSet: %w", err)
+	if err := mgr.AddReadyzCheck("check", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up ready check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("cache-sync", func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+		defer cancel()
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return errors.New("manager cache has not synced")
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to set up cache ready check: %w", err)
 	}
 
+	// Sharding
+	// The shard assigner only labels objects,
+	// all other controllers are running in the shard managers.
+	if opts.shardAssigner {
+		for _, kind := range []string{
+			"ObjectSet", "ClusterObjectSet", "ObjectSetPhase", "ClusterObjectSetPhase",
+		} {
+			if err = (sharding.NewShardAssigner(
+				mgr.GetClient(), ctrl.Log.WithName("controllers").WithName(kind+"ShardAssigner"),
+				mgr.GetScheme(), corev1alpha1.GroupVersion.WithKind(kind), opts.shards,
+			).SetupWithManager(mgr)); err != nil {
+				return fmt.Errorf("unable to create shard assigner for %s: %w", kind, err)
+			}
+		}
+		return startManager(log, mgr)
+	}
+
+	// ObjectSet and ObjectSetPhase controllers.
+	if err := components.AddToManager(mgr, components.Options{
+		NamespacedWatches:     opts.namespacedWatches,
+		TenantIsolation:       opts.tenantIsolation,
+		KindPolicy:            opts.kindPolicy,
+		ForceRemoveFinalizers: opts.forceRemoveFinalizers,
+		SettleDelays:          opts.settleDelays,
+		ApplyQPS:              opts.applyQPS,
+		ApplyBurst:            opts.applyBurst,
+		ApplyParallelism:      opts.applyParallelism,
+		ApplyRetries:          opts.applyRetries,
+	}); err != nil {
+		return err
+	}
+
+	return startManager(log, mgr)
+}
+
+func startManager(log logr.Logger, mgr ctrl.Manager) error {
 	log.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		return fmt.Errorf("problem running manager: %w", err)
 	}
 	return nil
 }
+
+// Runs the given server until the manager is stopped.
+func newHTTPServerRunnable(s *http.Server) manager.RunnableFunc {
+	return func(ctx context.Context) error {
+		errCh := make(chan error)
+		defer func() {
+			for range errCh {
+			} // drain errCh for GC
+		}()
+		go func() {
+			defer close(errCh)
+			errCh <- s.ListenAndServe()
+		}()
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+			s.Close()
+			return nil
+		}
+	}
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"package-operator.run/package-operator/internal/certrotation"
 	"package-operator.run/package-operator/internal/webhooks"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -22,6 +32,7 @@ var (
 
 func init() {
 	_ = corev1alpha1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
 }
 
 func main() {
@@ -29,6 +40,12 @@ func main() {
 		port      int
 		certDir   string
 		probeAddr string
+
+		selfSignedCerts       bool
+		namespace             string
+		certSecretName        string
+		serviceName           string
+		webhookConfigurations string
 	)
 
 	flag.IntVar(&port, "port", 8080, "The port the webhook server binds to")
@@ -36,11 +53,48 @@ func main() {
 		"The directory that contains the server key and certificate")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081",
 		"The address the probe endpoint binds to")
+	flag.BoolVar(&selfSignedCerts, "self-signed-certs", false,
+		"Create and rotate a self-signed serving certificate and inject its CA into the webhook configurations, "+
+			"instead of relying on certificates provisioned externally. Requires --namespace "+
+			"and a writable --cert-dir, e.g. an emptyDir volume instead of a mounted certificate Secret.")
+	flag.StringVar(&namespace, "namespace", os.Getenv("PKO_NAMESPACE"),
+		"The namespace the webhook server is deployed into.")
+	flag.StringVar(&certSecretName, "cert-secret-name", "webhook-server-self-signed-cert",
+		"Name of the Secret self-signed certificates are stored in.")
+	flag.StringVar(&serviceName, "service-name", "webhook-service",
+		"Name of the Service in front of the webhook server, self-signed certificates are issued for.")
+	flag.StringVar(&webhookConfigurations, "webhook-configurations",
+		"objectset-validating-webhook-configuration,objectsetphase-validating-webhook-configuration,"+
+			"clusterobjectset-validating-webhook-configuration,clusterobjectsetphase-validating-webhook-configuration",
+		"Names of ValidatingWebhookConfigurations to inject the self-signed CA bundle into.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	if len(certDir) == 0 {
+		// Default of the controller-runtime webhook server.
+		certDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+	}
+
+	var rotator *certrotation.Rotator
+	if selfSignedCerts {
+		if len(namespace) == 0 {
+			// Certificates would be issued for the wrong Service name.
+			setupLog.Error(nil, "-namespace or PKO_NAMESPACE is required with -self-signed-certs")
+			os.Exit(1)
+		}
+		var err error
+		rotator, err = setupCertRotation(
+			restConfig, namespace, certSecretName, serviceName,
+			certDir, strings.Split(webhookConfigurations, ","))
+		if err != nil {
+			setupLog.Error(err, "unable to set up self-signed certificates")
+			os.Exit(1)
+		}
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     "0",
 		Port:                   port,
@@ -54,6 +108,13 @@ func main() {
 
 	setupLog.Info("Setting up webhook server")
 
+	if rotator != nil {
+		if err := mgr.Add(rotator); err != nil {
+			setupLog.Error(err, "unable to set up certificate rotation")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -97,3 +158,35 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Issues initial certificates, so they are present when the webhook server starts.
+func setupCertRotation(
+	restConfig *rest.Config, namespace, secretName, serviceName, certDir string,
+	webhookConfigurations []string,
+) (*certrotation.Rotator, error) {
+	// The manager cache is not running yet.
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	rotator := certrotation.NewRotator(
+		c, ctrl.Log.WithName("cert-rotation"),
+		client.ObjectKey{Name: secretName, Namespace: namespace},
+		[]string{
+			fmt.Sprintf("%s.%s.svc", serviceName, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace),
+		},
+		certDir, webhookConfigurations,
+	)
+
+	// Other replicas may be creating or renewing certificates at the same time.
+	if err := retry.OnError(retry.DefaultBackoff, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, func() error {
+		return rotator.Sync(context.Background())
+	}); err != nil {
+		return nil, err
+	}
+	return rotator, nil
+}
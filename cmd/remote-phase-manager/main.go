@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrlconfigv1alpha1 "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	pkoapis "package-operator.run/apis"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/controllers/objectsetphases"
+	"package-operator.run/package-operator/internal/dynamiccache"
+	"package-operator.run/package-operator/internal/metrics"
+	"package-operator.run/package-operator/internal/ownerhandling"
+)
+
+type opts struct {
+	metricsAddr                 string
+	namespace                   string
+	enableLeaderElection        bool
+	leaseDuration               time.Duration
+	renewDeadline               time.Duration
+	retryPeriod                 time.Duration
+	probeAddr                   string
+	printVersion                bool
+	class                       string
+	targetClusterKubeconfigFile string
+	workQueueStallThreshold     time.Duration
+	controllerConcurrency       controllers.GroupKindConcurrency
+	kindPolicy                  controllers.KindPolicy
+	forceRemoveFinalizers       bool
+	applyQPS                    float64
+	applyBurst                  int
+	settleDelays                controllers.GroupKindDurations
+	applyParallelism            controllers.GroupKindLimits
+	applyRetries                controllers.GroupKindRetryPolicies
+}
+
+func main() {
+	opts := opts{
+		controllerConcurrency: controllers.GroupKindConcurrency{},
+		kindPolicy: controllers.KindPolicy{
+			Denied:  controllers.GroupKinds{},
+			Allowed: controllers.GroupKinds{},
+		},
+		settleDelays:     controllers.GroupKindDurations{},
+		applyParallelism: controllers.GroupKindLimits{},
+		applyRetries:     controllers.GroupKindRetryPolicies{},
+	}
+	flag.StringVar(&opts.metricsAddr, "metrics-addr", ":8080",
+		"The address the metric endpoint binds to.")
+	flag.StringVar(&opts.namespace, "namespace", os.Getenv("PKO_NAMESPACE"),
+		"The namespace the operator is deployed into.")
+	flag.BoolVar(&opts.enableLeaderElection, "enable-leader-election", false,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&opts.leaseDuration, "leader-election-lease-duration", 15*time.Second,
+		"Duration non-leader candidates wait before trying to acquire leadership.")
+	flag.DurationVar(&opts.renewDeadline, "leader-election-renew-deadline", 10*time.Second,
+		"Duration the leader retries refreshing leadership before giving it up.")
+	flag.DurationVar(&opts.retryPeriod, "leader-election-retry-period", 2*time.Second,
+		"Duration candidates wait between tries of acquiring or renewing leadership.")
+	flag.StringVar(&opts.probeAddr, "health-probe-bind-address", ":8081",
+		"The address the probe endpoint binds to.")
+	flag.DurationVar(&opts.workQueueStallThreshold, "work-queue-stall-threshold", 10*time.Minute,
+		"Liveness checks fail, when a controller is processing a single item for longer than this duration.")
+	flag.Var(opts.kindPolicy.Denied, "denied-kinds",
+		"Kinds of objects that must not be managed, e.g. \"ClusterRoleBinding.rbac.authorization.k8s.io,ValidatingWebhookConfiguration.admissionregistration.k8s.io\".")
+	flag.Var(opts.kindPolicy.Allowed, "allowed-kinds",
+		"If set, only objects of these kinds may be managed, e.g. \"Deployment.apps,ConfigMap\".")
+	flag.BoolVar(&opts.forceRemoveFinalizers, "force-remove-finalizers", false,
+		"Remove package-operator.run/ finalizers from objects stuck in deletion during teardown for longer than "+
+			controllers.StuckDeletionThreshold.String()+".")
+	flag.Float64Var(&opts.applyQPS, "apply-qps", 0,
+		"Maximum number of object writes per second across all controllers. 0 disables the limit.")
+	flag.IntVar(&opts.applyBurst, "apply-burst", 10,
+		"Maximum number of object writes allowed in a burst, when --apply-qps is set.")
+	flag.Var(opts.applyParallelism, "apply-parallelism",
+		"Maximum number of concurrent object writes per kind across all controllers, "+
+			"e.g. \"CustomResourceDefinition.apiextensions.k8s.io=1\". Other kinds are not limited.")
+	flag.Var(opts.applyRetries, "apply-retries",
+		"Retries of object writes failing with transient errors per kind, as RETRIESxBACKOFF with the backoff doubling "+
+			"after every retry, e.g. \"ValidatingWebhookConfiguration.admissionregistration.k8s.io=3x2s\". "+
+			"Writes of other kinds are retried by requeuing the reconcile.")
+	flag.Var(opts.settleDelays, "settle-delays",
+		"Time to wait after creating objects of these kinds, before reconciling further objects of the phase, "+
+			"e.g. \"CustomResourceDefinition.apiextensions.k8s.io=5s,ValidatingWebhookConfiguration.admissionregistration.k8s.io=10s\".")
+	flag.Var(opts.controllerConcurrency, "controller-concurrency",
+		"Maximum concurrent reconciles per controller, e.g. \"ObjectSet=10,ObjectSetPhase=5\". Defaults to 1.")
+	flag.BoolVar(&opts.printVersion, "version", false, "print version information and exit")
+	flag.StringVar(&opts.class, "class", "",
+		"Class of ObjectSetPhases this instance is responsible for.")
+	flag.StringVar(&opts.targetClusterKubeconfigFile, "target-cluster-kubeconfig-file", "",
+		"Path to a kubeconfig file of the cluster objects are reconciled into, usually mounted from a Secret. "+
+			"Phases may reference a kubeconfig Secret of their own in .spec.targetCluster instead. "+
+			"If empty, phases without .spec.targetCluster are not reconciled.")
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	scheme := runtime.NewScheme()
+	setupLog := ctrl.Log.WithName("setup")
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := pkoapis.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+
+	if opts.printVersion {
+		version := "binary compiled without version info"
+
+		if info, ok := debug.ReadBuildInfo(); ok {
+			version = info.String()
+		}
+
+		fmt.Fprintln(os.Stderr, version)
+		os.Exit(2)
+	}
+
+	if len(opts.class) == 0 {
+		setupLog.Error(nil, "-class is required")
+		os.Exit(1)
+	}
+
+	if err := run(setupLog, scheme, opts); err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+}
+
+func run(log logr.Logger, scheme *runtime.Scheme, opts opts) error {
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+		Scheme:                     scheme,
+		MetricsBindAddress:         opts.metricsAddr,
+		HealthProbeBindAddress:     opts.probeAddr,
+		LivenessEndpointName:       "/livez",
+		Port:                       9443,
+		LeaderElectionResourceLock: "leases",
+		LeaderElection:             opts.enableLeaderElection,
+		LeaderElectionNamespace:    opts.namespace,
+		LeaderElectionID:           "8a4hp84a6s.remote-phase-manager-" + opts.class,
+		// Release the lease when shutting down,
+		// so a standby replica can take over without waiting for the lease to expire.
+		LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:                 &opts.leaseDuration,
+		RenewDeadline:                 &opts.renewDeadline,
+		RetryPeriod:                   &opts.retryPeriod,
+		Controller: ctrlconfigv1alpha1.ControllerConfigurationSpec{
+			GroupKindConcurrency: opts.controllerConcurrency,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating manager: %w", err)
+	}
+
+	// Metrics
+	metricsRecorder := metrics.NewRecorder()
+	metricsRecorder.Register()
+
+	// Events
+	recorder := mgr.GetEventRecorderFor("remote-phase-manager")
+
+	// Target Clusters
+	// Object writes may be capped to protect small API servers during big rollouts,
+	// the limits are shared by all target clusters.
+	applyClientFunc := newApplyClientFunc(opts)
+	newTargetCluster := func(kubeconfig []byte) (*objectsetphases.TargetCluster, error) {
+		// Kubeconfig Secrets are provided by tenants.
+		targetCfg, err := objectsetphases.RESTConfigFromTenantKubeconfig(kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("loading target cluster kubeconfig: %w", err)
+		}
+		return connectTargetCluster(scheme, targetCfg, applyClientFunc)
+	}
+
+	// Phases without a target cluster of their own are reconciled on the default target cluster.
+	defaultTarget := &objectsetphases.TargetCluster{}
+	if len(opts.targetClusterKubeconfigFile) > 0 {
+		targetCfg, err := clientcmd.BuildConfigFromFlags("", opts.targetClusterKubeconfigFile)
+		if err != nil {
+			return fmt.Errorf("reading target cluster kubeconfig: %w", err)
+		}
+		if defaultTarget, err = connectTargetCluster(scheme, targetCfg, applyClientFunc); err != nil {
+			return err
+		}
+	}
+
+	// Health and Ready checks
+	// Liveness is served on /livez and fails when controllers stop processing work.
+	// Readiness requires all caches to have synced their initial state.
+	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up health check: %w", err)
+	}
+	if err := mgr.AddHealthzCheck(
+		"workqueues", metrics.NewWorkQueueStallChecker(opts.workQueueStallThreshold).Check); err != nil {
+		return fmt.Errorf("unable to set up work queue health check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("check", healthz.Ping); err != nil {
+		return fmt.Errorf("unable to set up ready check: %w", err)
+	}
+	if err := mgr.AddReadyzCheck("cache-sync", func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), time.Second)
+		defer cancel()
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return errors.New("manager cache has not synced")
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("unable to set up cache ready check: %w", err)
+	}
+	if dc, ok := defaultTarget.DynamicCache.(*dynamiccache.Cache); ok {
+		if err := mgr.AddReadyzCheck("dynamic-cache-sync", dc.SyncedChecker); err != nil {
+			return fmt.Errorf("unable to set up dynamic cache ready check: %w", err)
+		}
+	}
+
+	// ObjectSetPhase
+	// Owner references can't point across clusters,
+	// so ownership is recorded in annotations instead.
+	if err = (objectsetphases.NewObjectSetPhaseController(
+		ctrl.Log.WithName("controllers").WithName("ObjectSetPhase"),
+		mgr.GetScheme(), defaultTarget.DynamicCache, opts.class,
+		mgr.GetClient(), defaultTarget.Writer, defaultTarget.Reader, ownerhandling.NewAnnotation(mgr.GetScheme()),
+		metricsRecorder, recorder, defaultTarget.HealthChecker,
+		opts.kindPolicy, opts.forceRemoveFinalizers, opts.settleDelays, newTargetCluster,
+	).SetupWithManager(mgr)); err != nil {
+		return fmt.Errorf("unable to create controller for ObjectSetPhase: %w", err)
+	}
+	if err = (objectsetphases.NewClusterObjectSetPhaseController(
+		ctrl.Log.WithName("controllers").WithName("ClusterObjectSetPhase"),
+		mgr.GetScheme(), defaultTarget.DynamicCache, opts.class,
+		mgr.GetClient(), defaultTarget.Writer, defaultTarget.Reader, ownerhandling.NewAnnotation(mgr.GetScheme()),
+		metricsRecorder, recorder, defaultTarget.HealthChecker,
+		opts.kindPolicy, opts.forceRemoveFinalizers, opts.settleDelays, newTargetCluster,
+	).SetupWithManager(mgr)); err != nil {
+		return fmt.Errorf("unable to create controller for ClusterObjectSetPhase: %w", err)
+	}
+
+	log.Info("starting manager")
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		return fmt.Errorf("problem running manager: %w", err)
+	}
+	return nil
+}
+
+// Wraps the given client to cap its writes at the configured rate and parallelism
+// and to retry failed writes. All clients share the same limits.
+func newApplyClientFunc(opts opts) func(c client.Client) client.Client {
+	var rateLimiter flowcontrol.RateLimiter
+	if opts.applyQPS > 0 {
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(opts.applyQPS), opts.applyBurst)
+	}
+	parallelismLimiter := controllers.NewParallelismLimiter(opts.applyParallelism)
+	return func(c client.Client) client.Client {
+		if rateLimiter != nil {
+			c = controllers.NewRateLimitedClient(c, rateLimiter)
+		}
+		if len(opts.applyParallelism) > 0 {
+			c = controllers.NewParallelismLimitedClient(c, parallelismLimiter)
+		}
+		if len(opts.applyRetries) > 0 {
+			// Outermost, so no slot is held while waiting to retry.
+			c = controllers.NewRetryingClient(c, opts.applyRetries)
+		}
+		return c
+	}
+}
+
+// Connects to the target cluster of the given config.
+func connectTargetCluster(
+	scheme *runtime.Scheme, targetCfg *rest.Config,
+	applyClientFunc func(c client.Client) client.Client,
+) (*objectsetphases.TargetCluster, error) {
+	targetMapper, err := apiutil.NewDynamicRESTMapper(targetCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating target cluster rest mapper: %w", err)
+	}
+	targetClient, err := client.New(targetCfg, client.Options{
+		Scheme: scheme,
+		Mapper: targetMapper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating target cluster client: %w", err)
+	}
+
+	targetHealthChecker, err := objectsetphases.NewRemoteClusterHealthChecker(targetCfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating target cluster health checker: %w", err)
+	}
+
+	// DynamicCache on the target cluster.
+	dc := dynamiccache.NewCache(
+		targetCfg, scheme, targetMapper,
+		dynamiccache.SelectorsByGVK{
+			// Only cache objects with our label selector,
+			// so we prevent our caches from exploding!
+			schema.GroupVersionKind{}: dynamiccache.Selector{
+				Label: labels.SelectorFromSet(labels.Set{
+					controllers.DynamicCacheLabel: "True",
+				}),
+			},
+		},
+		dynamiccache.StripFieldsByGVK{
+			// Not used by any controller, but can make up
+			// a large share of an objects memory footprint.
+			schema.GroupVersionKind{}: dynamiccache.StripFields{
+				ManagedFields:         true,
+				LastAppliedAnnotation: true,
+			},
+		})
+
+	return &objectsetphases.TargetCluster{
+		DynamicCache:  dc,
+		Writer:        applyClientFunc(targetClient),
+		Reader:        targetClient,
+		HealthChecker: targetHealthChecker,
+		Stop:          dc.Stop,
+	}, nil
+}
@@ -0,0 +1,181 @@
+// The package components wires Package Operator controllers into a controller-runtime manager.
+// It is used by the package-operator-manager binary and allows other operators
+// to embed selected Package Operator controllers into their own manager.
+package components
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/flowcontrol"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/controllers/objectsetphases"
+	"package-operator.run/package-operator/internal/controllers/objectsets"
+	"package-operator.run/package-operator/internal/dynamiccache"
+	"package-operator.run/package-operator/internal/metrics"
+	"package-operator.run/package-operator/internal/ownerhandling"
+)
+
+type (
+	// Kinds of objects that may or may not be managed.
+	KindPolicy = controllers.KindPolicy
+	// Set of GroupKinds.
+	GroupKinds = controllers.GroupKinds
+	// Durations by GroupKind.
+	GroupKindDurations = controllers.GroupKindDurations
+	// Limits by GroupKind.
+	GroupKindLimits = controllers.GroupKindLimits
+	// Retry policies by GroupKind.
+	GroupKindRetryPolicies = controllers.GroupKindRetryPolicies
+	// Retries of writes failing with transient errors.
+	RetryPolicy = controllers.RetryPolicy
+)
+
+// Options configure the controllers added by AddToManager.
+type Options struct {
+	// Skips the ObjectSet and ClusterObjectSet controllers.
+	DisableObjectSets bool
+	// Skips the ObjectSetPhase and ClusterObjectSetPhase controllers of the default class.
+	DisableObjectSetPhases bool
+
+	// Watch objects only within the namespaces of the ObjectSets managing them,
+	// instead of cluster-wide.
+	NamespacedWatches bool
+	// Restrict ObjectSets to namespaced objects within their own namespace.
+	TenantIsolation bool
+	// Kinds of objects that may or may not be managed.
+	KindPolicy KindPolicy
+	// Remove Package Operator finalizers from objects stuck in deletion during teardown.
+	ForceRemoveFinalizers bool
+	// Time to wait after creating objects of these kinds, before reconciling further objects.
+	SettleDelays GroupKindDurations
+	// Maximum number of object writes per second across all controllers.
+	// 0 disables the limit.
+	ApplyQPS float64
+	// Maximum number of object writes allowed in a burst, when ApplyQPS is set.
+	ApplyBurst int
+	// Maximum number of concurrent object writes per kind across all controllers.
+	// Kinds without a limit are written without restriction.
+	ApplyParallelism GroupKindLimits
+	// Retries of object writes failing with transient errors per kind.
+	// Writes of other kinds fail right away and are retried by requeuing the reconcile.
+	ApplyRetries GroupKindRetryPolicies
+}
+
+// Adds the Package Operator controllers selected by opts to the given manager.
+// The scheme of the manager must include the Package Operator APIs, see package-operator.run/apis.
+// Metrics are registered into the controller-runtime metrics registry,
+// so AddToManager must only be called once per process.
+func AddToManager(mgr ctrl.Manager, opts Options) error {
+	log := ctrl.Log.WithName("controllers")
+
+	// Metrics
+	metricsRecorder := metrics.NewRecorder()
+	metricsRecorder.Register()
+
+	// Events
+	recorder := mgr.GetEventRecorderFor("package-operator")
+
+	// DynamicCache
+	dc := dynamiccache.NewCache(
+		mgr.GetConfig(), mgr.GetScheme(), mgr.GetRESTMapper(),
+		dynamiccache.SelectorsByGVK{
+			// Only cache objects with our label selector,
+			// so we prevent our caches from exploding!
+			schema.GroupVersionKind{}: dynamiccache.Selector{
+				Label: labels.SelectorFromSet(labels.Set{
+					controllers.DynamicCacheLabel: "True",
+				}),
+			},
+		},
+		dynamiccache.StripFieldsByGVK{
+			// Not used by any controller, but can make up
+			// a large share of an objects memory footprint.
+			schema.GroupVersionKind{}: dynamiccache.StripFields{
+				ManagedFields:         true,
+				LastAppliedAnnotation: true,
+			},
+		},
+		dynamiccache.NamespacedWatches(opts.NamespacedWatches))
+	if err := mgr.AddReadyzCheck("dynamic-cache-sync", dc.SyncedChecker); err != nil {
+		return fmt.Errorf("unable to set up dynamic cache ready check: %w", err)
+	}
+
+	// Rate limiting and retries
+	// Object writes may be capped to protect small API servers during big rollouts
+	// and retried for kinds that need time to take effect, like webhooks.
+	applyClient := newApplyClientFunc(opts)
+
+	// ObjectSet
+	// ObjectSets may specify a ServiceAccount to manage their objects with.
+	if !opts.DisableObjectSets {
+		impersonatingClient := controllers.NewImpersonatingClient(
+			mgr.GetClient(), mgr.GetConfig(), mgr.GetScheme(), mgr.GetRESTMapper())
+		if err := (objectsets.NewObjectSetController(
+			applyClient(impersonatingClient), mgr.GetAPIReader(), log.WithName("ObjectSet"),
+			mgr.GetScheme(), dc, metricsRecorder, recorder,
+			opts.TenantIsolation, opts.KindPolicy, opts.ForceRemoveFinalizers, opts.SettleDelays,
+		).SetupWithManager(mgr)); err != nil {
+			return fmt.Errorf("unable to create controller for ObjectSet: %w", err)
+		}
+		if err := (objectsets.NewClusterObjectSetController(
+			applyClient(mgr.GetClient()), mgr.GetAPIReader(), log.WithName("ClusterObjectSet"),
+			mgr.GetScheme(), dc, metricsRecorder, recorder,
+			opts.KindPolicy, opts.ForceRemoveFinalizers, opts.SettleDelays,
+		).SetupWithManager(mgr)); err != nil {
+			return fmt.Errorf("unable to create controller for ClusterObjectSet: %w", err)
+		}
+	}
+
+	// ObjectSetPhase
+	// Phases of the "default" class are reconciled on the same cluster,
+	// other classes are handled by remote-phase-manager instances.
+	if !opts.DisableObjectSetPhases {
+		if err := (objectsetphases.NewObjectSetPhaseController(
+			log.WithName("ObjectSetPhase"),
+			mgr.GetScheme(), dc, objectsetphases.DefaultObjectSetPhaseClass,
+			mgr.GetClient(), applyClient(mgr.GetClient()), mgr.GetAPIReader(), ownerhandling.NewNative(mgr.GetScheme()),
+			metricsRecorder, recorder, nil,
+			opts.KindPolicy, opts.ForceRemoveFinalizers, opts.SettleDelays, nil,
+		).SetupWithManager(mgr)); err != nil {
+			return fmt.Errorf("unable to create controller for ObjectSetPhase: %w", err)
+		}
+		if err := (objectsetphases.NewClusterObjectSetPhaseController(
+			log.WithName("ClusterObjectSetPhase"),
+			mgr.GetScheme(), dc, objectsetphases.DefaultObjectSetPhaseClass,
+			mgr.GetClient(), applyClient(mgr.GetClient()), mgr.GetAPIReader(), ownerhandling.NewNative(mgr.GetScheme()),
+			metricsRecorder, recorder, nil,
+			opts.KindPolicy, opts.ForceRemoveFinalizers, opts.SettleDelays, nil,
+		).SetupWithManager(mgr)); err != nil {
+			return fmt.Errorf("unable to create controller for ClusterObjectSetPhase: %w", err)
+		}
+	}
+	return nil
+}
+
+// Wraps the given client to cap its writes at the configured rate and parallelism
+// and to retry failed writes. All clients share the same limits.
+func newApplyClientFunc(opts Options) func(c client.Client) client.Client {
+	var rateLimiter flowcontrol.RateLimiter
+	if opts.ApplyQPS > 0 {
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(opts.ApplyQPS), opts.ApplyBurst)
+	}
+	parallelismLimiter := controllers.NewParallelismLimiter(opts.ApplyParallelism)
+	return func(c client.Client) client.Client {
+		if rateLimiter != nil {
+			c = controllers.NewRateLimitedClient(c, rateLimiter)
+		}
+		if len(opts.ApplyParallelism) > 0 {
+			c = controllers.NewParallelismLimitedClient(c, parallelismLimiter)
+		}
+		if len(opts.ApplyRetries) > 0 {
+			// Outermost, so no slot is held while waiting to retry.
+			c = controllers.NewRetryingClient(c, opts.ApplyRetries)
+		}
+		return c
+	}
+}
@@ -45,6 +45,11 @@ spec:
         image: quay.io/openshift/package-operator-webhook:latest
         ports:
         - containerPort: 8080
+        env:
+        - name: PKO_NAMESPACE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.namespace
         volumeMounts:
         - name: tls
           mountPath: "/tmp/k8s-webhook-server/serving-certs/"
@@ -69,6 +74,11 @@ spec:
             cpu: 100m
             memory: 30Mi
       volumes:
+      # Serving certificate provisioned externally.
+      # When running with --self-signed-certs, the webhook server writes its own certificate
+      # into this directory, replace the Secret with a writable volume and drop readOnly from the mount:
+      #   - name: tls
+      #     emptyDir: {}
       - name: tls
         secret:
           secretName: webhook-server-cert
@@ -28,7 +28,7 @@ spec:
               fieldPath: metadata.namespace
         livenessProbe:
           httpGet:
-            path: /healthz
+            path: /livez
             port: 8081
           initialDelaySeconds: 15
           periodSeconds: 20
@@ -0,0 +1,289 @@
+// The package conformance contains tests, verifying that a controller handling
+// a class of ObjectSetPhases follows the contract documented on the ObjectSetPhase API.
+// The tests run against a live cluster with the controller under test running.
+// They create their own parent ObjectSets and ObjectSetPhases in the given namespace
+// and clean up after themselves.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// Default time to wait for the controller under test.
+const DefaultTimeout = time.Minute
+
+// ObjectSetPhaseOptions configure the ObjectSetPhase conformance tests.
+type ObjectSetPhaseOptions struct {
+	// Client for the cluster ObjectSets and ObjectSetPhases are created on.
+	// Its scheme must include the core/v1 and Package Operator APIs.
+	Client client.Client
+	// Client for the cluster objects of the phases are reconciled on.
+	// Defaults to Client, set it for controllers reconciling objects on another cluster.
+	TargetClient client.Client
+	// Class handled by the controller under test.
+	Class string
+	// Namespace to create test objects in, must exist on both clusters.
+	Namespace string
+	// Maximum time to wait for the controller under test.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Interval between checks while waiting for the controller under test.
+	// Defaults to one second.
+	Interval time.Duration
+}
+
+func (o *ObjectSetPhaseOptions) defaults() {
+	if o.TargetClient == nil {
+		o.TargetClient = o.Client
+	}
+	if o.Timeout == 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Interval == 0 {
+		o.Interval = time.Second
+	}
+}
+
+// Runs the ObjectSetPhase conformance tests against the controller handling opts.Class.
+// The tests build on each other and cover, in order:
+// reporting availability and controlled objects, taking over objects from previous revisions,
+// handing back objects taken over by a later revision and deleting controlled objects on teardown.
+func RunObjectSetPhaseTests(t *testing.T, opts ObjectSetPhaseOptions) {
+	t.Helper()
+	opts.defaults()
+	s := &objectSetPhaseSuite{
+		opts: opts,
+		name: "pko-conformance-" + rand.String(5),
+	}
+	t.Cleanup(func() { s.cleanup(t) })
+
+	steps := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{"ReportsAvailableAndControlledObjects", s.testReportsAvailableAndControlledObjects},
+		{"TakesOverFromPreviousRevision", s.testTakesOverFromPreviousRevision},
+		{"HandsBackObjectsTakenOver", s.testHandsBackObjectsTakenOver},
+		{"DeletesControlledObjectsOnTeardown", s.testDeletesControlledObjectsOnTeardown},
+	}
+	for _, step := range steps {
+		if !t.Run(step.name, step.run) {
+			// Later steps build on the state of earlier steps.
+			return
+		}
+	}
+}
+
+type objectSetPhaseSuite struct {
+	opts ObjectSetPhaseOptions
+	// Prefix of all object names.
+	name string
+}
+
+func (s *objectSetPhaseSuite) revisionName(revision int64) string {
+	return fmt.Sprintf("%s-%d", s.name, revision)
+}
+
+func (s *objectSetPhaseSuite) configMapKey() client.ObjectKey {
+	return client.ObjectKey{Name: s.name, Namespace: s.opts.Namespace}
+}
+
+// Revision 1 creates the ConfigMap.
+func (s *objectSetPhaseSuite) testReportsAvailableAndControlledObjects(t *testing.T) {
+	ctx := context.Background()
+	objectSetPhase := s.createRevision(ctx, t, 1, nil)
+	s.waitForAvailable(ctx, t, objectSetPhase)
+
+	assert.Contains(t, objectSetPhase.Status.ControlledObjects, s.configMapReference(),
+		".status.controlledObjects must list all controlled objects")
+	s.assertConfigMapRevision(ctx, t, 1)
+}
+
+// Revision 2 takes over the ConfigMap from revision 1.
+func (s *objectSetPhaseSuite) testTakesOverFromPreviousRevision(t *testing.T) {
+	ctx := context.Background()
+	objectSetPhase := s.createRevision(ctx, t, 2, []corev1alpha1.PreviousRevisionReference{
+		{Name: s.revisionName(1)},
+	})
+	s.waitForAvailable(ctx, t, objectSetPhase)
+
+	assert.Contains(t, objectSetPhase.Status.ControlledObjects, s.configMapReference(),
+		"objects of previous revisions must be taken over")
+	s.assertConfigMapRevision(ctx, t, 2)
+}
+
+// Revision 1 is torn down and must leave the ConfigMap taken over by revision 2 alone.
+func (s *objectSetPhaseSuite) testHandsBackObjectsTakenOver(t *testing.T) {
+	ctx := context.Background()
+	s.deleteAndWait(ctx, t, s.revisionName(1))
+	s.assertConfigMapRevision(ctx, t, 2)
+}
+
+// Revision 2 is torn down and must delete the ConfigMap it controls.
+func (s *objectSetPhaseSuite) testDeletesControlledObjectsOnTeardown(t *testing.T) {
+	ctx := context.Background()
+	s.deleteAndWait(ctx, t, s.revisionName(2))
+
+	err := s.opts.TargetClient.Get(ctx, s.configMapKey(), &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err),
+		"controlled objects must be deleted on teardown, got: %v", err)
+}
+
+// Creates an empty parent ObjectSet and an ObjectSetPhase controlled by it,
+// the phase contains a single ConfigMap, recording the revision in its data.
+func (s *objectSetPhaseSuite) createRevision(
+	ctx context.Context, t *testing.T,
+	revision int64, previous []corev1alpha1.PreviousRevisionReference,
+) *corev1alpha1.ObjectSetPhase {
+	t.Helper()
+
+	// Parent ObjectSets are looked up to find previous revisions.
+	objectSet := &corev1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.revisionName(revision),
+			Namespace: s.opts.Namespace,
+		},
+		Spec: corev1alpha1.ObjectSetSpec{
+			ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+				Phases:             []corev1alpha1.ObjectSetTemplatePhase{},
+				AvailabilityProbes: []corev1alpha1.ObjectSetProbe{},
+			},
+		},
+	}
+	require.NoError(t, s.opts.Client.Create(ctx, objectSet))
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.name,
+			Namespace: s.opts.Namespace,
+		},
+		Data: map[string]string{"revision": fmt.Sprint(revision)},
+	}
+	cmJSON, err := json.Marshal(cm)
+	require.NoError(t, err)
+
+	objectSetPhase := &corev1alpha1.ObjectSetPhase{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.revisionName(revision),
+			Namespace: s.opts.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: corev1alpha1.GroupVersion.String(),
+					Kind:       "ObjectSet",
+					Name:       objectSet.Name,
+					UID:        objectSet.UID,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+		Spec: corev1alpha1.ObjectSetPhaseSpec{
+			Revision:           revision,
+			Previous:           previous,
+			AvailabilityProbes: []corev1alpha1.ObjectSetProbe{},
+			ObjectSetTemplatePhase: corev1alpha1.ObjectSetTemplatePhase{
+				Name:  "conformance",
+				Class: s.opts.Class,
+				Objects: []corev1alpha1.ObjectSetObject{
+					{Object: runtime.RawExtension{Raw: cmJSON}},
+				},
+			},
+		},
+	}
+	require.NoError(t, s.opts.Client.Create(ctx, objectSetPhase))
+	return objectSetPhase
+}
+
+func (s *objectSetPhaseSuite) configMapReference() corev1alpha1.ObjectSetObjectReference {
+	return corev1alpha1.ObjectSetObjectReference{
+		Kind:      "ConfigMap",
+		Name:      s.name,
+		Namespace: s.opts.Namespace,
+	}
+}
+
+// Waits for the Available condition to be reported as True for the current generation.
+func (s *objectSetPhaseSuite) waitForAvailable(
+	ctx context.Context, t *testing.T, objectSetPhase *corev1alpha1.ObjectSetPhase,
+) {
+	t.Helper()
+	err := wait.PollImmediate(s.opts.Interval, s.opts.Timeout, func() (bool, error) {
+		if err := s.opts.Client.Get(
+			ctx, client.ObjectKeyFromObject(objectSetPhase), objectSetPhase); err != nil {
+			return false, err
+		}
+		cond := meta.FindStatusCondition(
+			objectSetPhase.Status.Conditions, corev1alpha1.ObjectSetAvailable)
+		return cond != nil && cond.Status == metav1.ConditionTrue &&
+			cond.ObservedGeneration == objectSetPhase.Generation &&
+			objectSetPhase.Status.ObservedGeneration == objectSetPhase.Generation, nil
+	})
+	require.NoError(t, err,
+		"waiting for ObjectSetPhase %s to report Available for its current generation", objectSetPhase.Name)
+}
+
+// Deletes the ObjectSetPhase of the given name and waits until teardown is complete.
+func (s *objectSetPhaseSuite) deleteAndWait(ctx context.Context, t *testing.T, name string) {
+	t.Helper()
+	objectSetPhase := &corev1alpha1.ObjectSetPhase{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.opts.Namespace},
+	}
+	require.NoError(t, s.opts.Client.Delete(ctx, objectSetPhase))
+	err := wait.PollImmediate(s.opts.Interval, s.opts.Timeout, func() (bool, error) {
+		err := s.opts.Client.Get(ctx, client.ObjectKeyFromObject(objectSetPhase), objectSetPhase)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	require.NoError(t, err, "waiting for teardown of ObjectSetPhase %s", name)
+}
+
+func (s *objectSetPhaseSuite) assertConfigMapRevision(
+	ctx context.Context, t *testing.T, revision int64,
+) {
+	t.Helper()
+	cm := &corev1.ConfigMap{}
+	if assert.NoError(t, s.opts.TargetClient.Get(ctx, s.configMapKey(), cm)) {
+		assert.Equal(t, fmt.Sprint(revision), cm.Data["revision"],
+			"ConfigMap must reflect the revision controlling it")
+	}
+}
+
+func (s *objectSetPhaseSuite) cleanup(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+	objs := []client.Object{
+		&corev1alpha1.ObjectSetPhase{ObjectMeta: metav1.ObjectMeta{
+			Name: s.revisionName(1), Namespace: s.opts.Namespace}},
+		&corev1alpha1.ObjectSetPhase{ObjectMeta: metav1.ObjectMeta{
+			Name: s.revisionName(2), Namespace: s.opts.Namespace}},
+		&corev1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{
+			Name: s.revisionName(1), Namespace: s.opts.Namespace}},
+		&corev1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{
+			Name: s.revisionName(2), Namespace: s.opts.Namespace}},
+	}
+	for _, obj := range objs {
+		if err := s.opts.Client.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			t.Errorf("cleaning up %s: %v", obj.GetName(), err)
+		}
+	}
+}
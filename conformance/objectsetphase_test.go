@@ -0,0 +1,100 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	pkoapis "package-operator.run/apis"
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/controllers/objectsetphases"
+	"package-operator.run/package-operator/internal/metrics"
+	"package-operator.run/package-operator/internal/ownerhandling"
+)
+
+// Runs the conformance tests against the built-in ObjectSetPhase controller.
+// Instead of running a manager, all ObjectSetPhases are reconciled
+// whenever the tests read from the cluster.
+func TestRunObjectSetPhaseTests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkoapis.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	controller := objectsetphases.NewObjectSetPhaseController(
+		logr.Discard(), scheme, &fakeDynamicCache{Reader: c},
+		objectsetphases.DefaultObjectSetPhaseClass,
+		c, c, c, ownerhandling.NewNative(scheme),
+		metrics.NewRecorder(), record.NewFakeRecorder(100), nil,
+		controllers.KindPolicy{}, false, nil, nil,
+	)
+
+	RunObjectSetPhaseTests(t, ObjectSetPhaseOptions{
+		Client: &reconcilingClient{
+			Client: c,
+			reconcile: func(ctx context.Context) error {
+				objectSetPhases := &corev1alpha1.ObjectSetPhaseList{}
+				if err := c.List(ctx, objectSetPhases); err != nil {
+					return err
+				}
+				for _, objectSetPhase := range objectSetPhases.Items {
+					if _, err := controller.Reconcile(ctx, ctrl.Request{
+						NamespacedName: client.ObjectKeyFromObject(&objectSetPhase),
+					}); err != nil {
+						t.Logf("reconciling %s: %v", objectSetPhase.Name, err)
+					}
+				}
+				return nil
+			},
+		},
+		Class:     objectsetphases.DefaultObjectSetPhaseClass,
+		Namespace: "test",
+		Timeout:   time.Second,
+		Interval:  10 * time.Millisecond,
+	})
+}
+
+// Reconciles before every read, so the tests observe the outcome.
+type reconcilingClient struct {
+	client.Client
+	reconcile func(ctx context.Context) error
+}
+
+func (c *reconcilingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if err := c.reconcile(ctx); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+// The fake client does not assign UIDs like the API server does,
+// but status writes are batched per UID.
+func (c *reconcilingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	obj.SetUID(uuid.NewUUID())
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+type fakeDynamicCache struct {
+	client.Reader
+}
+
+func (c *fakeDynamicCache) Source() source.Source { return nil }
+
+func (c *fakeDynamicCache) Free(context.Context, client.Object) error { return nil }
+
+func (c *fakeDynamicCache) Watch(context.Context, client.Object, runtime.Object) error { return nil }
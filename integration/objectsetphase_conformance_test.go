@@ -0,0 +1,17 @@
+package integration
+
+import (
+	"testing"
+
+	"package-operator.run/package-operator/conformance"
+	"package-operator.run/package-operator/internal/controllers/objectsetphases"
+)
+
+// Runs the exported ObjectSetPhase conformance tests against the deployed manager.
+func TestObjectSetPhase_conformance(t *testing.T) {
+	conformance.RunObjectSetPhaseTests(t, conformance.ObjectSetPhaseOptions{
+		Client:    Client,
+		Class:     objectsetphases.DefaultObjectSetPhaseClass,
+		Namespace: "default",
+	})
+}
@@ -0,0 +1,220 @@
+package certrotation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys of the Secret holding certificates.
+const (
+	caCertKey         = "ca.crt"
+	caKeyKey          = "ca.key"
+	caPreviousCertKey = "ca-previous.crt"
+	certKey           = corev1.TLSCertKey
+	keyKey            = corev1.TLSPrivateKeyKey
+)
+
+const (
+	defaultCAValidity      = 365 * 24 * time.Hour
+	defaultCertValidity    = 30 * 24 * time.Hour
+	defaultRefreshInterval = time.Hour
+)
+
+// Rotator maintains a self-signed certificate authority and a serving certificate
+// for webhook servers, so no external certificate management is needed.
+// Certificates are stored in a Secret shared by all replicas, written into the certificate directory
+// of the webhook server and the CA bundle is injected into ValidatingWebhookConfigurations.
+// Certificates are renewed, when less than a third of their lifetime is left.
+type Rotator struct {
+	client    client.Client
+	log       logr.Logger
+	secretKey client.ObjectKey
+	dnsNames  []string
+	certDir   string
+	// Names of ValidatingWebhookConfigurations to inject the CA bundle into.
+	webhookConfigurations []string
+
+	caValidity      time.Duration
+	certValidity    time.Duration
+	refreshInterval time.Duration
+	now             func() time.Time
+}
+
+func NewRotator(
+	c client.Client, log logr.Logger,
+	secretKey client.ObjectKey, dnsNames []string,
+	certDir string, webhookConfigurations []string,
+) *Rotator {
+	return &Rotator{
+		client:                c,
+		log:                   log,
+		secretKey:             secretKey,
+		dnsNames:              dnsNames,
+		certDir:               certDir,
+		webhookConfigurations: webhookConfigurations,
+
+		caValidity:      defaultCAValidity,
+		certValidity:    defaultCertValidity,
+		refreshInterval: defaultRefreshInterval,
+		now:             time.Now,
+	}
+}
+
+// Periodically renews certificates until the context is cancelled.
+// Implements manager.Runnable, Sync must have been called once before the webhook server starts.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Sync(ctx); err != nil {
+				r.log.Error(err, "rotating webhook certificates")
+			}
+		}
+	}
+}
+
+// Every webhook server replica has to write its own certificate files.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ensures valid certificates exist, are written to disk and trusted by all webhook configurations.
+func (r *Rotator) Sync(ctx context.Context) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, r.secretKey, secret)
+	exists := err == nil
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("getting certificate Secret: %w", err)
+	}
+	if !exists {
+		secret.Name = r.secretKey.Name
+		secret.Namespace = r.secretKey.Namespace
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	changed, err := r.ensureCertificates(secret)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !exists:
+		if err := r.client.Create(ctx, secret); err != nil {
+			return fmt.Errorf("creating certificate Secret: %w", err)
+		}
+	case changed:
+		// Update uses optimistic locking, so replicas can't overwrite each others certificates.
+		if err := r.client.Update(ctx, secret); err != nil {
+			return fmt.Errorf("updating certificate Secret: %w", err)
+		}
+	}
+
+	// Trust new CAs before serving certificates signed by them.
+	caBundle := r.caBundle(secret)
+	for _, name := range r.webhookConfigurations {
+		if err := r.injectCABundle(ctx, name, caBundle); err != nil {
+			return err
+		}
+	}
+	return r.writeCertFiles(secret)
+}
+
+// Renews certificates stored in the secret as needed.
+func (r *Rotator) ensureCertificates(secret *corev1.Secret) (changed bool, err error) {
+	now := r.now()
+
+	ca, err := parseKeyPair(secret.Data[caCertKey], secret.Data[caKeyKey])
+	if err != nil || ca.needsRefresh(now) {
+		if err == nil {
+			// Keep trusting the old CA, until certificates signed by it are replaced everywhere.
+			secret.Data[caPreviousCertKey] = ca.certPEM
+		}
+		ca, err = newCA(r.dnsNames[0]+"-ca", now, r.caValidity)
+		if err != nil {
+			return false, fmt.Errorf("creating CA: %w", err)
+		}
+		secret.Data[caCertKey] = ca.certPEM
+		secret.Data[caKeyKey] = ca.keyPEM
+		changed = true
+	}
+
+	cert, err := parseKeyPair(secret.Data[certKey], secret.Data[keyKey])
+	if err != nil || cert.needsRefresh(now) || cert.cert.CheckSignatureFrom(ca.cert) != nil {
+		cert, err = newServingCert(ca, r.dnsNames, now, r.certValidity)
+		if err != nil {
+			return false, fmt.Errorf("creating serving certificate: %w", err)
+		}
+		secret.Data[certKey] = cert.certPEM
+		secret.Data[keyKey] = cert.keyPEM
+		changed = true
+	}
+
+	if prev, ok := secret.Data[caPreviousCertKey]; ok {
+		if prevCert, err := parseCert(prev); err != nil || now.After(prevCert.NotAfter) {
+			delete(secret.Data, caPreviousCertKey)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+func (r *Rotator) caBundle(secret *corev1.Secret) []byte {
+	bundle := append([]byte{}, secret.Data[caCertKey]...)
+	return append(bundle, secret.Data[caPreviousCertKey]...)
+}
+
+func (r *Rotator) injectCABundle(ctx context.Context, name string, caBundle []byte) error {
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: name}, config); err != nil {
+		return fmt.Errorf("getting ValidatingWebhookConfiguration %s: %w", name, err)
+	}
+
+	var changed bool
+	for i := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			config.Webhooks[i].ClientConfig.CABundle = caBundle
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.client.Update(ctx, config); err != nil {
+		return fmt.Errorf("injecting CA bundle into ValidatingWebhookConfiguration %s: %w", name, err)
+	}
+	return nil
+}
+
+// Writes the serving certificate for the webhook server, which reloads it on change.
+func (r *Rotator) writeCertFiles(secret *corev1.Secret) error {
+	if err := os.MkdirAll(r.certDir, 0o700); err != nil {
+		return fmt.Errorf("creating certificate directory: %w", err)
+	}
+	// Write the key first, the webhook server only picks up matching pairs.
+	for _, key := range []string{keyKey, certKey} {
+		path := filepath.Join(r.certDir, key)
+		current, err := os.ReadFile(path)
+		if err == nil && bytes.Equal(current, secret.Data[key]) {
+			continue
+		}
+		if err := os.WriteFile(path, secret.Data[key], 0o600); err != nil {
+			return fmt.Errorf("writing %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package certrotation
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestRotator_Sync(t *testing.T) {
+	c := testutil.NewClient()
+	certDir := t.TempDir()
+	r := NewRotator(c, ctrl.Log,
+		client.ObjectKey{Name: "certs", Namespace: "pko"},
+		[]string{"webhook-service.pko.svc"}, certDir, []string{"webhook"})
+
+	c.On("Get", mock.Anything, client.ObjectKey{Name: "certs", Namespace: "pko"}, mock.AnythingOfType("*v1.Secret")).
+		Return(errors.NewNotFound(schema.GroupResource{}, ""))
+	var secret *corev1.Secret
+	c.On("Create", mock.Anything, mock.AnythingOfType("*v1.Secret"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			secret = args.Get(1).(*corev1.Secret)
+		}).
+		Return(nil)
+	c.On("Get", mock.Anything, client.ObjectKey{Name: "webhook"}, mock.AnythingOfType("*v1.ValidatingWebhookConfiguration")).
+		Run(func(args mock.Arguments) {
+			config := args.Get(2).(*admissionregistrationv1.ValidatingWebhookConfiguration)
+			config.Webhooks = []admissionregistrationv1.ValidatingWebhook{{}, {}}
+		}).
+		Return(nil)
+	var config *admissionregistrationv1.ValidatingWebhookConfiguration
+	c.On("Update", mock.Anything, mock.AnythingOfType("*v1.ValidatingWebhookConfiguration"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			config = args.Get(1).(*admissionregistrationv1.ValidatingWebhookConfiguration)
+		}).
+		Return(nil)
+
+	require.NoError(t, r.Sync(context.Background()))
+	require.NotNil(t, secret)
+	require.NotNil(t, config)
+
+	// CA bundle injected into all webhooks.
+	for _, wh := range config.Webhooks {
+		assert.Equal(t, secret.Data[caCertKey], wh.ClientConfig.CABundle)
+	}
+
+	// Serving certificate written to disk and trusted by the CA bundle.
+	certPEM, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data[certKey], certPEM)
+	keyPEM, err := os.ReadFile(filepath.Join(certDir, corev1.TLSPrivateKeyKey))
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data[keyKey], keyPEM)
+
+	cert, err := parseCert(certPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(config.Webhooks[0].ClientConfig.CABundle))
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName: "webhook-service.pko.svc",
+		Roots:   roots,
+	})
+	assert.NoError(t, err)
+}
+
+func TestRotator_ensureCertificates(t *testing.T) {
+	now := time.Now()
+	r := NewRotator(nil, ctrl.Log, client.ObjectKey{},
+		[]string{"webhook-service.pko.svc"}, "", nil)
+	r.now = func() time.Time { return now }
+
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	changed, err := r.ensureCertificates(secret)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	initial := secret.DeepCopy()
+
+	t.Run("valid certificates are kept", func(t *testing.T) {
+		changed, err := r.ensureCertificates(secret)
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, initial.Data, secret.Data)
+	})
+
+	t.Run("serving certificate renewed", func(t *testing.T) {
+		secret := initial.DeepCopy()
+		r.now = func() time.Time { return now.Add(r.certValidity * 3 / 4) }
+		changed, err := r.ensureCertificates(secret)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, initial.Data[caCertKey], secret.Data[caCertKey])
+		assert.NotEqual(t, initial.Data[certKey], secret.Data[certKey])
+	})
+
+	t.Run("CA renewed and previous CA still trusted", func(t *testing.T) {
+		secret := initial.DeepCopy()
+		r.now = func() time.Time { return now.Add(r.caValidity * 3 / 4) }
+		changed, err := r.ensureCertificates(secret)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.NotEqual(t, initial.Data[caCertKey], secret.Data[caCertKey])
+		assert.NotEqual(t, initial.Data[certKey], secret.Data[certKey])
+		assert.Equal(t, initial.Data[caCertKey], secret.Data[caPreviousCertKey])
+		assert.Equal(t,
+			append(append([]byte{}, secret.Data[caCertKey]...), initial.Data[caCertKey]...),
+			r.caBundle(secret))
+
+		// Previous CA is dropped once expired.
+		r.now = func() time.Time { return now.Add(r.caValidity + time.Hour) }
+		_, err = r.ensureCertificates(secret)
+		require.NoError(t, err)
+		assert.NotContains(t, secret.Data, caPreviousCertKey)
+	})
+}
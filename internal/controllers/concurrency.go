@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// GroupKindConcurrency configures MaxConcurrentReconciles per controller,
+// keyed by the GroupKind ("Kind.group") of the reconciled object.
+// Implements flag.Value, parsing a list like "ObjectSet=10,ClusterObjectSet=2".
+// Kinds without a group default to the Package Operator API group.
+type GroupKindConcurrency map[string]int
+
+func (c GroupKindConcurrency) String() string {
+	pairs := make([]string, 0, len(c))
+	for gk, concurrency := range c {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", gk, concurrency))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c GroupKindConcurrency) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid concurrency %q, expected Kind=N", pair)
+		}
+		gk := strings.TrimSpace(parts[0])
+		concurrency, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || concurrency < 1 {
+			return fmt.Errorf("invalid concurrency %q, expected a positive number", pair)
+		}
+		if !strings.Contains(gk, ".") {
+			gk = gk + "." + corev1alpha1.GroupVersion.Group
+		}
+		c[gk] = concurrency
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupKindConcurrency(t *testing.T) {
+	c := GroupKindConcurrency{}
+	require.NoError(t, c.Set("ObjectSet=10, Deployment.apps=2"))
+
+	assert.Equal(t, GroupKindConcurrency{
+		"ObjectSet.package-operator.run": 10,
+		"Deployment.apps":                2,
+	}, c)
+	assert.Equal(t, "Deployment.apps=2,ObjectSet.package-operator.run=10", c.String())
+
+	assert.Error(t, c.Set("ObjectSet"))
+	assert.Error(t, c.Set("ObjectSet=0"))
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// Maximum number of field paths reported per field manager of a conflicting object.
+const conflictMaxFields = 10
+
+// Implemented by owners reporting objects they failed to adopt.
+type conflictsRecorder interface {
+	RecordConflict(conflict corev1alpha1.ObjectSetConflict)
+}
+
+// Records which controller and which field managers own an object that could not be adopted.
+// Managed fields are stripped from cached objects, so the object is read live from the API server.
+func (r *PhaseReconciler) recordConflict(
+	ctx context.Context, owner PhaseObjectOwner,
+	currentObj *unstructured.Unstructured,
+) error {
+	liveObj := &unstructured.Unstructured{}
+	liveObj.SetGroupVersionKind(currentObj.GroupVersionKind())
+	if err := r.uncachedReader.Get(
+		ctx, client.ObjectKeyFromObject(currentObj), liveObj); err != nil {
+		return fmt.Errorf("getting conflicting %s: %w", currentObj.GroupVersionKind(), err)
+	}
+
+	conflict := corev1alpha1.ObjectSetConflict{
+		ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+			Group:     liveObj.GroupVersionKind().Group,
+			Kind:      liveObj.GetKind(),
+			Name:      liveObj.GetName(),
+			Namespace: liveObj.GetNamespace(),
+		},
+	}
+	if controller := metav1.GetControllerOf(liveObj); controller != nil {
+		conflict.Controller = controller.Kind + "/" + controller.Name
+	}
+	for _, entry := range liveObj.GetManagedFields() {
+		conflict.Managers = append(conflict.Managers, corev1alpha1.ObjectSetFieldManager{
+			Manager:   entry.Manager,
+			Operation: string(entry.Operation),
+			Fields:    managedFieldPaths(entry.FieldsV1),
+		})
+		r.metricsRecorder.RecordObjectConflict(
+			owner.ClientObject(), liveObj.GroupVersionKind(), entry.Manager)
+	}
+
+	if recorder, ok := owner.(conflictsRecorder); ok {
+		recorder.RecordConflict(conflict)
+	}
+	return nil
+}
+
+// Returns the paths of fields in a managedFields entry, e.g. spec.replicas.
+// List items and map entries are reported as their parent field.
+func managedFieldPaths(fieldsV1 *metav1.FieldsV1) []string {
+	if fieldsV1 == nil {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(fieldsV1.Raw, &fields); err != nil {
+		return nil
+	}
+
+	var paths []string
+	collectFieldPaths(fields, "", &paths)
+	sort.Strings(paths)
+	if len(paths) > conflictMaxFields {
+		paths = paths[:conflictMaxFields]
+	}
+	return paths
+}
+
+func collectFieldPaths(fields map[string]interface{}, prefix string, paths *[]string) {
+	var hasChildFields bool
+	for k, v := range fields {
+		if !strings.HasPrefix(k, "f:") {
+			continue
+		}
+		name := strings.TrimPrefix(k, "f:")
+		hasChildFields = true
+		path := name
+		if len(prefix) > 0 {
+			path = prefix + "." + name
+		}
+		child, _ := v.(map[string]interface{})
+		collectFieldPaths(child, path, paths)
+	}
+	if !hasChildFields && len(prefix) > 0 {
+		*paths = append(*paths, prefix)
+	}
+}
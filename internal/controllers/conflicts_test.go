@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestPhaseReconciler_recordConflict(t *testing.T) {
+	c := testutil.NewClient()
+	recorder := &metricsRecorderMock{}
+	r := &PhaseReconciler{uncachedReader: c, metricsRecorder: recorder}
+
+	ownerObj := &unstructured.Unstructured{}
+	owner := &conflictsRecorderOwnerMock{}
+	owner.On("ClientObject").Return(ownerObj)
+
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetGroupVersionKind(gvk)
+	currentObj.SetName("operator")
+	currentObj.SetNamespace("test")
+
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*unstructured.Unstructured)
+			currentObj.DeepCopyInto(obj)
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "ObjectSet", Name: "other-1", Controller: pointer.Bool(true)},
+			})
+			obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-edit",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:metadata":{"f:labels":{"f:app":{}}},"f:spec":{"f:replicas":{}}}`),
+					},
+				},
+			})
+		}).
+		Return(nil)
+	recorder.On("RecordObjectConflict", ownerObj, gvk, "kubectl-edit")
+
+	err := r.recordConflict(context.Background(), owner, currentObj)
+	require.NoError(t, err)
+
+	assert.Equal(t, []corev1alpha1.ObjectSetConflict{
+		{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Group: "apps", Kind: "Deployment", Name: "operator", Namespace: "test",
+			},
+			Controller: "ObjectSet/other-1",
+			Managers: []corev1alpha1.ObjectSetFieldManager{
+				{
+					Manager:   "kubectl-edit",
+					Operation: "Update",
+					Fields:    []string{"metadata.labels.app", "spec.replicas"},
+				},
+			},
+		},
+	}, owner.conflicts)
+	recorder.AssertExpectations(t)
+}
+
+func Test_managedFieldPaths(t *testing.T) {
+	paths := managedFieldPaths(&metav1.FieldsV1{
+		Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"manager\"}":{".":{},"f:image":{}}}}}}}`),
+	})
+	assert.Equal(t, []string{"spec.template.spec.containers"}, paths)
+	assert.Nil(t, managedFieldPaths(nil))
+}
+
+type conflictsRecorderOwnerMock struct {
+	phaseObjectOwnerMock
+	conflicts []corev1alpha1.ObjectSetConflict
+}
+
+func (m *conflictsRecorderOwnerMock) RecordConflict(conflict corev1alpha1.ObjectSetConflict) {
+	m.conflicts = append(m.conflicts, conflict)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,10 +14,33 @@ import (
 const (
 	// This label is set on all dynamic objects to limit caches.
 	DynamicCacheLabel = "package-operator.run/cache"
+	// This label assigns objects to a shard when running sharded managers.
+	ShardLabel = "package-operator.run/shard"
 	// Common finalizer to free allocated caches when objects are deleted.
 	CachedFinalizer = "package-operator.run/cached"
+	// Namespaces annotated with "true" restrict ObjectSets within them
+	// to objects in their own namespace, even when tenant isolation is not enforced operator-wide.
+	TenantIsolationAnnotation = "package-operator.run/tenant-isolation"
+	// Objects annotated with TeardownPolicyKeep are orphaned instead of deleted,
+	// when their ObjectSet is archived or deleted.
+	TeardownPolicyAnnotation = "package-operator.run/teardown-policy"
+	TeardownPolicyKeep       = "Keep"
+	// CustomResourceDefinitions annotated with "true" are deleted during teardown,
+	// even when instances of them still exist.
+	AllowCRDDeletionAnnotation = "package-operator.run/allow-crd-deletion"
+	// Orders objects within phases with .ordered set, lowest weight first.
+	WeightAnnotation = "package-operator.run/weight"
+	// Objects annotated with "true" are always read from the API server instead of the cache,
+	// e.g. when they are changed too frequently by other controllers for the cache to keep up.
+	CacheBypassAnnotation = "package-operator.run/cache-bypass"
 )
 
+// Objects in deletion for longer than this threshold are reported as stuck during teardown.
+const StuckDeletionThreshold = 10 * time.Minute
+
+// Prefix of all finalizers owned by Package Operator.
+const finalizerPrefix = "package-operator.run/"
+
 // Ensures the given finalizer is set and persisted on the given object.
 func EnsureFinalizer(
 	ctx context.Context, c client.Client,
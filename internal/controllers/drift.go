@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// Maximum number of drifted objects listed in the DriftDetected condition message.
+const maxReportedDrift = 10
+
+// Reports objects differing from their desired state via the DriftDetected condition,
+// while the owner is paused. Drift is corrected right away otherwise, so the condition is removed.
+func ReportDriftDetected(
+	conditions *[]metav1.Condition, generation int64,
+	paused bool, drifted []string,
+) {
+	if !paused {
+		meta.RemoveStatusCondition(conditions, corev1alpha1.ObjectSetDriftDetected)
+		return
+	}
+
+	if len(drifted) == 0 {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               corev1alpha1.ObjectSetDriftDetected,
+			Status:             metav1.ConditionFalse,
+			Reason:             "NoDrift",
+			Message:            "All objects match their desired state.",
+			ObservedGeneration: generation,
+		})
+		return
+	}
+
+	summary := drifted
+	if len(summary) > maxReportedDrift {
+		summary = append(summary[:maxReportedDrift:maxReportedDrift],
+			fmt.Sprintf("and %d more", len(drifted)-maxReportedDrift))
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               corev1alpha1.ObjectSetDriftDetected,
+		Status:             metav1.ConditionTrue,
+		Reason:             "DriftDetected",
+		Message:            fmt.Sprintf("%d objects differ from their desired state: %s", len(drifted), strings.Join(summary, ", ")),
+		ObservedGeneration: generation,
+	})
+}
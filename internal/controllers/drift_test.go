@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func TestReportDriftDetected(t *testing.T) {
+	var conditions []metav1.Condition
+
+	ReportDriftDetected(&conditions, 1, true, nil)
+	cond := meta.FindStatusCondition(conditions, corev1alpha1.ObjectSetDriftDetected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+
+	var drifted []string
+	for i := 0; i < 12; i++ {
+		drifted = append(drifted, fmt.Sprintf("ConfigMap test/cm-%d", i))
+	}
+	ReportDriftDetected(&conditions, 1, true, drifted)
+	cond = meta.FindStatusCondition(conditions, corev1alpha1.ObjectSetDriftDetected)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionTrue, cond.Status)
+	assert.Contains(t, cond.Message, "12 objects differ from their desired state: ConfigMap test/cm-0,")
+	assert.Contains(t, cond.Message, "ConfigMap test/cm-9, and 2 more")
+	assert.Len(t, drifted, 12, "input not modified")
+
+	ReportDriftDetected(&conditions, 1, false, drifted)
+	assert.Empty(t, conditions)
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Event reasons emitted by Package Operator controllers.
+// Reasons are part of the user facing API, alerting may depend on them.
+const (
+	// Object became available and passes all probes.
+	EventReasonAvailable = "Available"
+	// Object was available before, but stopped passing probes.
+	EventReasonNotAvailable = "NotAvailable"
+	// A phase delegated to an ObjectSetPhase became available.
+	EventReasonPhaseAvailable = "PhaseAvailable"
+	// Lifecycle state changed to Paused.
+	EventReasonPaused = "Paused"
+	// Lifecycle state changed to Archived and all objects have been cleaned up.
+	EventReasonArchived = "Archived"
+	// An object is already owned by someone else and can't be adopted.
+	EventReasonCollisionDetected = "CollisionDetected"
+	// An object's kind is forbidden by the cluster-wide kind policy.
+	EventReasonKindNotAllowed = "KindNotAllowed"
+	// Objects were left behind on deletion or archival, because their target cluster is unavailable.
+	EventReasonObjectsOrphaned = "ObjectsOrphaned"
+)
+
+// Returns the condition of the given type from newConditions,
+// if its status differs from the status in oldConditions
+// or if it is not reported in oldConditions at all.
+func ConditionTransition(
+	oldConditions, newConditions []metav1.Condition, conditionType string,
+) (cond *metav1.Condition, transitioned bool) {
+	newCond := meta.FindStatusCondition(newConditions, conditionType)
+	if newCond == nil {
+		return nil, false
+	}
+	oldCond := meta.FindStatusCondition(oldConditions, conditionType)
+	if oldCond != nil && oldCond.Status == newCond.Status {
+		return nil, false
+	}
+	return newCond, true
+}
+
+// Returns true, if the given error was caused by
+// an object collision between owners or revisions.
+func IsCollisionError(err error) bool {
+	var (
+		notOwnedErr  ObjectNotOwnedByPreviousRevisionError
+		collisionErr RevisionCollisionError
+	)
+	return errors.As(err, &notOwnedErr) || errors.As(err, &collisionErr)
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConditionTransition(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldConditions []metav1.Condition
+		newConditions []metav1.Condition
+		transitioned  bool
+	}{
+		{
+			name: "not reported",
+		},
+		{
+			name: "newly reported",
+			newConditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionTrue},
+			},
+			transitioned: true,
+		},
+		{
+			name: "status changed",
+			oldConditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionFalse},
+			},
+			newConditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionTrue},
+			},
+			transitioned: true,
+		},
+		{
+			name: "status unchanged",
+			oldConditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionTrue, Message: "old"},
+			},
+			newConditions: []metav1.Condition{
+				{Type: "Available", Status: metav1.ConditionTrue, Message: "new"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cond, transitioned := ConditionTransition(
+				test.oldConditions, test.newConditions, "Available")
+			assert.Equal(t, test.transitioned, transitioned)
+			if test.transitioned {
+				assert.Equal(t, &test.newConditions[0], cond)
+			} else {
+				assert.Nil(t, cond)
+			}
+		})
+	}
+}
+
+func TestIsCollisionError(t *testing.T) {
+	assert.True(t, IsCollisionError(
+		fmt.Errorf("wrapped: %w", RevisionCollisionError{})))
+	assert.True(t, IsCollisionError(ObjectNotOwnedByPreviousRevisionError{}))
+	assert.False(t, IsCollisionError(fmt.Errorf("other")))
+}
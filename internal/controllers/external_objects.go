@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/probing"
+)
+
+// External objects are not watched,
+// so phases waiting for them are checked again after this interval.
+const ExternalObjectsRecheckInterval = 30 * time.Second
+
+// Checks the external objects a phase waits for.
+// Reports every selector not matching any object and every object failing its probes.
+func (r *PhaseReconciler) checkExternalObjects(
+	ctx context.Context, owner PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (failedProbes []string, err error) {
+	for _, external := range phase.ExternalObjects {
+		selector, err := metav1.LabelSelectorAsSelector(&external.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("parsing selector for external %s: %w", external.Kind, err)
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(external.APIVersion)
+		list.SetKind(external.Kind + "List")
+		gk := list.GroupVersionKind().GroupKind()
+		err = r.uncachedReader.List(ctx, list,
+			client.InNamespace(owner.ClientObject().GetNamespace()),
+			client.MatchingLabelsSelector{Selector: selector})
+		if meta.IsNoMatchError(err) {
+			failedProbes = append(failedProbes,
+				fmt.Sprintf("%s %s: kind not installed", gk.Group, external.Kind))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing external %s: %w", external.Kind, err)
+		}
+		if len(list.Items) == 0 {
+			failedProbes = append(failedProbes,
+				fmt.Sprintf("%s %s: no object matches %q", gk.Group, external.Kind, selector))
+			continue
+		}
+
+		probe := probing.ParseProbes(ctx, external.Probes)
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if success, message := probe.Probe(obj); !success {
+				failedProbes = append(failedProbes,
+					fmt.Sprintf("%s %s %s/%s: %s",
+						gk.Group, external.Kind, obj.GetNamespace(), obj.GetName(), message))
+			}
+		}
+	}
+	return failedProbes, nil
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestPhaseReconciler_checkExternalObjects(t *testing.T) {
+	phase := corev1alpha1.ObjectSetTemplatePhase{
+		ExternalObjects: []corev1alpha1.ObjectSetExternalObjects{
+			{
+				APIVersion: "cert-manager.io/v1",
+				Kind:       "Certificate",
+				Selector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "example"},
+				},
+				Probes: []corev1alpha1.Probe{
+					{
+						Condition: &corev1alpha1.ProbeConditionSpec{
+							Type:   "Ready",
+							Status: "True",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	newCertificate := func(name, readyStatus string) unstructured.Unstructured {
+		return unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":       name,
+					"namespace":  "test",
+					"generation": int64(1),
+				},
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{
+							"type":               "Ready",
+							"status":             readyStatus,
+							"observedGeneration": int64(1),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		items        []unstructured.Unstructured
+		failedProbes []string
+	}{
+		{
+			name: "missing",
+			failedProbes: []string{
+				`cert-manager.io Certificate: no object matches "app=example"`,
+			},
+		},
+		{
+			name: "not ready",
+			items: []unstructured.Unstructured{
+				newCertificate("ready", "True"),
+				newCertificate("not-ready", "False"),
+			},
+			failedProbes: []string{
+				`cert-manager.io Certificate test/not-ready: condition "Ready" == "True": wrong status`,
+			},
+		},
+		{
+			name: "ready",
+			items: []unstructured.Unstructured{
+				newCertificate("ready", "True"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			r := &PhaseReconciler{uncachedReader: c}
+			owner := &phaseObjectOwnerMock{}
+			ownerObj := &unstructured.Unstructured{}
+			ownerObj.SetNamespace("test")
+			owner.On("ClientObject").Return(ownerObj)
+
+			c.
+				On("List", mock.Anything, mock.AnythingOfType("*unstructured.UnstructuredList"), mock.Anything).
+				Run(func(args mock.Arguments) {
+					list := args.Get(1).(*unstructured.UnstructuredList)
+					assert.Equal(t, schema.GroupVersionKind{
+						Group: "cert-manager.io", Version: "v1", Kind: "CertificateList",
+					}, list.GroupVersionKind())
+					list.Items = test.items
+				}).
+				Return(nil)
+
+			failedProbes, err := r.checkExternalObjects(context.Background(), owner, phase)
+			require.NoError(t, err)
+			assert.Equal(t, test.failedProbes, failedProbes)
+		})
+	}
+}
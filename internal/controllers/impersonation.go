@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type serviceAccountContextKey struct{}
+
+// Returns a new context, instructing ImpersonatingClient
+// to send writes on behalf of the given ServiceAccount.
+// An empty name keeps the identity of Package Operator.
+func WithServiceAccount(ctx context.Context, sa types.NamespacedName) context.Context {
+	if len(sa.Name) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, serviceAccountContextKey{}, sa)
+}
+
+func serviceAccountFromContext(ctx context.Context) (types.NamespacedName, bool) {
+	sa, ok := ctx.Value(serviceAccountContextKey{}).(types.NamespacedName)
+	return sa, ok
+}
+
+// ImpersonatingClient sends writes on behalf of the ServiceAccount stored in the context,
+// while reads are served by the wrapped client using the identity of Package Operator.
+type ImpersonatingClient struct {
+	client.Client
+	newClient func(config *rest.Config) (client.Client, error)
+	config    *rest.Config
+
+	mux     sync.Mutex
+	clients map[types.NamespacedName]client.Client
+}
+
+func NewImpersonatingClient(
+	c client.Client, config *rest.Config,
+	scheme *runtime.Scheme, mapper meta.RESTMapper,
+) *ImpersonatingClient {
+	return &ImpersonatingClient{
+		Client: c,
+		newClient: func(config *rest.Config) (client.Client, error) {
+			return client.New(config, client.Options{Scheme: scheme, Mapper: mapper})
+		},
+		config:  config,
+		clients: map[types.NamespacedName]client.Client{},
+	}
+}
+
+func (c *ImpersonatingClient) Create(
+	ctx context.Context, obj client.Object, opts ...client.CreateOption,
+) error {
+	w, err := c.writerFor(ctx)
+	if err != nil {
+		return err
+	}
+	return w.Create(ctx, obj, opts...)
+}
+
+func (c *ImpersonatingClient) Delete(
+	ctx context.Context, obj client.Object, opts ...client.DeleteOption,
+) error {
+	w, err := c.writerFor(ctx)
+	if err != nil {
+		return err
+	}
+	return w.Delete(ctx, obj, opts...)
+}
+
+func (c *ImpersonatingClient) Update(
+	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
+) error {
+	w, err := c.writerFor(ctx)
+	if err != nil {
+		return err
+	}
+	return w.Update(ctx, obj, opts...)
+}
+
+func (c *ImpersonatingClient) Patch(
+	ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption,
+) error {
+	w, err := c.writerFor(ctx)
+	if err != nil {
+		return err
+	}
+	return w.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *ImpersonatingClient) DeleteAllOf(
+	ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption,
+) error {
+	w, err := c.writerFor(ctx)
+	if err != nil {
+		return err
+	}
+	return w.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *ImpersonatingClient) writerFor(ctx context.Context) (client.Writer, error) {
+	sa, ok := serviceAccountFromContext(ctx)
+	if !ok {
+		return c.Client, nil
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if w, ok := c.clients[sa]; ok {
+		return w, nil
+	}
+
+	config := rest.CopyConfig(c.config)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name),
+	}
+	w, err := c.newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating client for ServiceAccount %s: %w", sa, err)
+	}
+	c.clients[sa] = w
+	return w, nil
+}
+
+// Implemented by owners that can specify a ServiceAccount to impersonate.
+type serviceAccountOwner interface {
+	GetServiceAccountName() string
+}
+
+// Returns a new context, impersonating the ServiceAccount specified by the owner, if any.
+func WithOwnerServiceAccount(ctx context.Context, owner PhaseObjectOwner) context.Context {
+	saOwner, ok := owner.(serviceAccountOwner)
+	if !ok {
+		return ctx
+	}
+	return WithServiceAccount(ctx, types.NamespacedName{
+		Namespace: owner.ClientObject().GetNamespace(),
+		Name:      saOwner.GetServiceAccountName(),
+	})
+}
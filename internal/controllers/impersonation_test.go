@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestImpersonatingClient(t *testing.T) {
+	defaultClient := testutil.NewClient()
+	saClient := testutil.NewClient()
+	var configs []*rest.Config
+	c := &ImpersonatingClient{
+		Client: defaultClient,
+		newClient: func(config *rest.Config) (client.Client, error) {
+			configs = append(configs, config)
+			return saClient, nil
+		},
+		config:  &rest.Config{Host: "https://kube-apiserver"},
+		clients: map[types.NamespacedName]client.Client{},
+	}
+
+	defaultClient.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	saClient.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	ctx := context.Background()
+	require.NoError(t, c.Create(ctx, &corev1.ConfigMap{}))
+	defaultClient.AssertNumberOfCalls(t, "Create", 1)
+
+	ctx = WithServiceAccount(ctx, types.NamespacedName{Namespace: "tenant", Name: "deployer"})
+	require.NoError(t, c.Create(ctx, &corev1.ConfigMap{}))
+	require.NoError(t, c.Create(ctx, &corev1.ConfigMap{}))
+	saClient.AssertNumberOfCalls(t, "Create", 2)
+
+	// Clients are reused per ServiceAccount.
+	require.Len(t, configs, 1)
+	assert.Equal(t, "https://kube-apiserver", configs[0].Host)
+	assert.Equal(t, "system:serviceaccount:tenant:deployer", configs[0].Impersonate.UserName)
+}
+
+func TestWithServiceAccount_empty(t *testing.T) {
+	ctx := WithServiceAccount(context.Background(), types.NamespacedName{Namespace: "tenant"})
+	_, ok := serviceAccountFromContext(ctx)
+	assert.False(t, ok)
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupKinds is a set of GroupKinds.
+// Implements flag.Value, parsing a list like "ClusterRoleBinding.rbac.authorization.k8s.io,Secret".
+type GroupKinds map[schema.GroupKind]struct{}
+
+func (gks GroupKinds) String() string {
+	list := make([]string, 0, len(gks))
+	for gk := range gks {
+		list = append(list, gk.String())
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func (gks GroupKinds) Set(value string) error {
+	for _, gk := range strings.Split(value, ",") {
+		gk = strings.TrimSpace(gk)
+		if len(gk) == 0 {
+			continue
+		}
+		gks[schema.ParseGroupKind(gk)] = struct{}{}
+	}
+	return nil
+}
+
+func (gks GroupKinds) Has(gk schema.GroupKind) bool {
+	_, ok := gks[gk]
+	return ok
+}
+
+// KindPolicy restricts which kinds of objects Package Operator may manage.
+type KindPolicy struct {
+	// Kinds that must never be managed.
+	Denied GroupKinds
+	// If not empty, only these kinds may be managed.
+	Allowed GroupKinds
+}
+
+// Returns a KindNotAllowedError, if objects of the given kind must not be managed.
+func (p KindPolicy) Check(gk schema.GroupKind) error {
+	if p.Denied.Has(gk) ||
+		(len(p.Allowed) > 0 && !p.Allowed.Has(gk)) {
+		return KindNotAllowedError{GroupKind: gk}
+	}
+	return nil
+}
+
+// KindNotAllowedError is returned when an object's kind is forbidden by the KindPolicy.
+type KindNotAllowedError struct {
+	GroupKind schema.GroupKind
+}
+
+func (e KindNotAllowedError) Error() string {
+	return fmt.Sprintf("managing %s objects is not allowed by cluster policy", e.GroupKind)
+}
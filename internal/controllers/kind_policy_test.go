@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGroupKinds(t *testing.T) {
+	gks := GroupKinds{}
+	require.NoError(t, gks.Set("ClusterRoleBinding.rbac.authorization.k8s.io, Secret"))
+
+	assert.True(t, gks.Has(schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}))
+	assert.True(t, gks.Has(schema.GroupKind{Kind: "Secret"}))
+	assert.Equal(t, "ClusterRoleBinding.rbac.authorization.k8s.io,Secret", gks.String())
+}
+
+func TestKindPolicy_Check(t *testing.T) {
+	secret := schema.GroupKind{Kind: "Secret"}
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+
+	assert.NoError(t, KindPolicy{}.Check(secret))
+
+	denied := KindPolicy{Denied: GroupKinds{secret: {}}}
+	assert.Equal(t, KindNotAllowedError{GroupKind: secret}, denied.Check(secret))
+	assert.NoError(t, denied.Check(deployment))
+
+	allowed := KindPolicy{Allowed: GroupKinds{deployment: {}}}
+	assert.Error(t, allowed.Check(secret))
+	assert.NoError(t, allowed.Check(deployment))
+}
@@ -0,0 +1,73 @@
+package objectsetphases
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var errKubeconfigNoCA = errors.New("certificate-authority-data is required")
+
+// Loads a kubeconfig provided by tenants via a Secret.
+// Kubeconfigs may execute commands, read local files or route requests through proxies,
+// all of which would run with the identity of the controller.
+// Only inline credentials and certificate authorities are accepted.
+func RESTConfigFromTenantKubeconfig(kubeconfig []byte) (*rest.Config, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	for name, authInfo := range config.AuthInfos {
+		if err := validateTenantAuthInfo(authInfo); err != nil {
+			return nil, fmt.Errorf("user %q: %w", name, err)
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if err := validateTenantCluster(cluster); err != nil {
+			return nil, fmt.Errorf("cluster %q: %w", name, err)
+		}
+	}
+	return clientcmd.NewDefaultClientConfig(*config, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+func validateTenantAuthInfo(authInfo *clientcmdapi.AuthInfo) error {
+	switch {
+	case authInfo.Exec != nil:
+		return unsupportedKubeconfigField("exec")
+	case authInfo.AuthProvider != nil:
+		return unsupportedKubeconfigField("auth-provider")
+	case len(authInfo.TokenFile) > 0:
+		return unsupportedKubeconfigField("tokenFile")
+	case len(authInfo.ClientCertificate) > 0:
+		return unsupportedKubeconfigField("client-certificate")
+	case len(authInfo.ClientKey) > 0:
+		return unsupportedKubeconfigField("client-key")
+	case len(authInfo.Impersonate) > 0 || len(authInfo.ImpersonateUID) > 0 ||
+		len(authInfo.ImpersonateGroups) > 0 || len(authInfo.ImpersonateUserExtra) > 0:
+		return unsupportedKubeconfigField("impersonation")
+	case len(authInfo.Username) > 0 || len(authInfo.Password) > 0:
+		return unsupportedKubeconfigField("username/password")
+	}
+	return nil
+}
+
+func validateTenantCluster(cluster *clientcmdapi.Cluster) error {
+	switch {
+	case len(cluster.ProxyURL) > 0:
+		return unsupportedKubeconfigField("proxy-url")
+	case len(cluster.CertificateAuthority) > 0:
+		return unsupportedKubeconfigField("certificate-authority")
+	case cluster.InsecureSkipTLSVerify:
+		return unsupportedKubeconfigField("insecure-skip-tls-verify")
+	case len(cluster.CertificateAuthorityData) == 0:
+		return errKubeconfigNoCA
+	}
+	return nil
+}
+
+func unsupportedKubeconfigField(field string) error {
+	return fmt.Errorf("%s is not supported, only inline credentials are allowed", field)
+}
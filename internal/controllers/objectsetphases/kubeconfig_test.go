@@ -0,0 +1,135 @@
+package objectsetphases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newTestKubeconfig(
+	modifyCluster func(c *clientcmdapi.Cluster), modifyAuthInfo func(a *clientcmdapi.AuthInfo),
+) *clientcmdapi.Config {
+	cluster := &clientcmdapi.Cluster{
+		Server:                   "https://target.example.com:6443",
+		CertificateAuthorityData: []byte("ca"),
+	}
+	authInfo := &clientcmdapi.AuthInfo{Token: "token"}
+	if modifyCluster != nil {
+		modifyCluster(cluster)
+	}
+	if modifyAuthInfo != nil {
+		modifyAuthInfo(authInfo)
+	}
+	return &clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"target": cluster},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"tenant": authInfo},
+		Contexts:       map[string]*clientcmdapi.Context{"target": {Cluster: "target", AuthInfo: "tenant"}},
+		CurrentContext: "target",
+	}
+}
+
+func TestRESTConfigFromTenantKubeconfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		modifyCluster  func(c *clientcmdapi.Cluster)
+		modifyAuthInfo func(a *clientcmdapi.AuthInfo)
+		errMsg         string
+	}{
+		{
+			name: "exec",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.Exec = &clientcmdapi.ExecConfig{Command: "sh", APIVersion: "client.authentication.k8s.io/v1"}
+			},
+			errMsg: "exec",
+		},
+		{
+			name: "auth-provider",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc"}
+			},
+			errMsg: "auth-provider",
+		},
+		{
+			name: "tokenFile",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.TokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+			},
+			errMsg: "tokenFile",
+		},
+		{
+			name: "client-certificate",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.ClientCertificate = "/etc/tls/tls.crt"
+			},
+			errMsg: "client-certificate",
+		},
+		{
+			name: "client-key",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.ClientKey = "/etc/tls/tls.key"
+			},
+			errMsg: "client-key",
+		},
+		{
+			name: "impersonate",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.Impersonate = "system:admin"
+			},
+			errMsg: "impersonation",
+		},
+		{
+			name: "impersonate groups",
+			modifyAuthInfo: func(a *clientcmdapi.AuthInfo) {
+				a.ImpersonateGroups = []string{"system:masters"}
+			},
+			errMsg: "impersonation",
+		},
+		{
+			name: "proxy-url",
+			modifyCluster: func(c *clientcmdapi.Cluster) {
+				c.ProxyURL = "http://attacker.example.com"
+			},
+			errMsg: "proxy-url",
+		},
+		{
+			name: "certificate-authority file",
+			modifyCluster: func(c *clientcmdapi.Cluster) {
+				c.CertificateAuthority = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+			},
+			errMsg: "certificate-authority",
+		},
+		{
+			name: "no inline CA",
+			modifyCluster: func(c *clientcmdapi.Cluster) {
+				c.CertificateAuthorityData = nil
+			},
+			errMsg: errKubeconfigNoCA.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			kubeconfig, err := clientcmd.Write(*newTestKubeconfig(test.modifyCluster, test.modifyAuthInfo))
+			require.NoError(t, err)
+
+			_, err = RESTConfigFromTenantKubeconfig(kubeconfig)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+
+	t.Run("inline credentials", func(t *testing.T) {
+		kubeconfig, err := clientcmd.Write(*newTestKubeconfig(nil, nil))
+		require.NoError(t, err)
+
+		cfg, err := RESTConfigFromTenantKubeconfig(kubeconfig)
+		require.NoError(t, err)
+		assert.Equal(t, "https://target.example.com:6443", cfg.Host)
+		assert.Equal(t, "token", cfg.BearerToken)
+		assert.Empty(t, cfg.BearerTokenFile)
+	})
+}
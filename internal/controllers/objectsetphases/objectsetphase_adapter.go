@@ -0,0 +1,246 @@
+package objectsetphases
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+type genericObjectSetPhase interface {
+	ClientObject() client.Object
+	GetConditions() *[]metav1.Condition
+	IsPaused() bool
+	IsArchived() bool
+	GetClass() string
+	GetPhase() corev1alpha1.ObjectSetTemplatePhase
+	GetAvailabilityProbes() []corev1alpha1.ObjectSetProbe
+	GetPrevious() []corev1alpha1.PreviousRevisionReference
+	// Returns .spec.revision,
+	// the revision number is handed down from the parent ObjectSet.
+	GetStatusRevision() int64
+	SetObservedGeneration(generation int64)
+	SetControlledObjects(controlled []corev1alpha1.ObjectSetObjectReference)
+}
+
+type genericObjectSetPhaseFactory func(
+	scheme *runtime.Scheme) genericObjectSetPhase
+
+var (
+	objectSetPhaseGVK        = corev1alpha1.GroupVersion.WithKind("ObjectSetPhase")
+	clusterObjectSetPhaseGVK = corev1alpha1.GroupVersion.WithKind("ClusterObjectSetPhase")
+)
+
+func newGenericObjectSetPhase(scheme *runtime.Scheme) genericObjectSetPhase {
+	obj, err := scheme.New(objectSetPhaseGVK)
+	if err != nil {
+		panic(err)
+	}
+
+	return &GenericObjectSetPhase{
+		ObjectSetPhase: *obj.(*corev1alpha1.ObjectSetPhase)}
+}
+
+func newGenericClusterObjectSetPhase(scheme *runtime.Scheme) genericObjectSetPhase {
+	obj, err := scheme.New(clusterObjectSetPhaseGVK)
+	if err != nil {
+		panic(err)
+	}
+
+	return &GenericClusterObjectSetPhase{
+		ClusterObjectSetPhase: *obj.(*corev1alpha1.ClusterObjectSetPhase)}
+}
+
+var (
+	_ genericObjectSetPhase = (*GenericObjectSetPhase)(nil)
+	_ genericObjectSetPhase = (*GenericClusterObjectSetPhase)(nil)
+)
+
+type GenericObjectSetPhase struct {
+	corev1alpha1.ObjectSetPhase
+}
+
+func (a *GenericObjectSetPhase) ClientObject() client.Object {
+	return &a.ObjectSetPhase
+}
+
+func (a *GenericObjectSetPhase) GetConditions() *[]metav1.Condition {
+	return &a.Status.Conditions
+}
+
+func (a *GenericObjectSetPhase) IsPaused() bool {
+	return a.Spec.LifecycleState == corev1alpha1.ObjectSetLifecycleStatePaused
+}
+
+func (a *GenericObjectSetPhase) IsArchived() bool {
+	return a.Spec.LifecycleState == corev1alpha1.ObjectSetLifecycleStateArchived
+}
+
+func (a *GenericObjectSetPhase) GetClass() string {
+	return a.Spec.Class
+}
+
+func (a *GenericObjectSetPhase) GetPhase() corev1alpha1.ObjectSetTemplatePhase {
+	return a.Spec.ObjectSetTemplatePhase
+}
+
+func (a *GenericObjectSetPhase) GetAvailabilityProbes() []corev1alpha1.ObjectSetProbe {
+	return a.Spec.AvailabilityProbes
+}
+
+func (a *GenericObjectSetPhase) GetPrevious() []corev1alpha1.PreviousRevisionReference {
+	return a.Spec.Previous
+}
+
+func (a *GenericObjectSetPhase) GetStatusRevision() int64 {
+	return a.Spec.Revision
+}
+
+func (a *GenericObjectSetPhase) SetObservedGeneration(generation int64) {
+	a.Status.ObservedGeneration = generation
+}
+
+func (a *GenericObjectSetPhase) SetControlledObjects(controlled []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.ControlledObjects = controlled
+}
+
+func (a *GenericObjectSetPhase) RecordControlledObject(obj client.Object) {
+	a.Status.ControlledObjects = append(a.Status.ControlledObjects, newObjectReference(obj))
+}
+
+type GenericClusterObjectSetPhase struct {
+	corev1alpha1.ClusterObjectSetPhase
+}
+
+func (a *GenericClusterObjectSetPhase) ClientObject() client.Object {
+	return &a.ClusterObjectSetPhase
+}
+
+func (a *GenericClusterObjectSetPhase) GetConditions() *[]metav1.Condition {
+	return &a.Status.Conditions
+}
+
+func (a *GenericClusterObjectSetPhase) IsPaused() bool {
+	return a.Spec.LifecycleState == corev1alpha1.ObjectSetLifecycleStatePaused
+}
+
+func (a *GenericClusterObjectSetPhase) IsArchived() bool {
+	return a.Spec.LifecycleState == corev1alpha1.ObjectSetLifecycleStateArchived
+}
+
+func (a *GenericClusterObjectSetPhase) GetClass() string {
+	return a.Spec.Class
+}
+
+func (a *GenericClusterObjectSetPhase) GetPhase() corev1alpha1.ObjectSetTemplatePhase {
+	return a.Spec.ObjectSetTemplatePhase
+}
+
+func (a *GenericClusterObjectSetPhase) GetAvailabilityProbes() []corev1alpha1.ObjectSetProbe {
+	return a.Spec.AvailabilityProbes
+}
+
+func (a *GenericClusterObjectSetPhase) GetPrevious() []corev1alpha1.PreviousRevisionReference {
+	return a.Spec.Previous
+}
+
+func (a *GenericClusterObjectSetPhase) GetStatusRevision() int64 {
+	return a.Spec.Revision
+}
+
+func (a *GenericClusterObjectSetPhase) SetObservedGeneration(generation int64) {
+	a.Status.ObservedGeneration = generation
+}
+
+func (a *GenericClusterObjectSetPhase) SetControlledObjects(controlled []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.ControlledObjects = controlled
+}
+
+func (a *GenericClusterObjectSetPhase) RecordControlledObject(obj client.Object) {
+	a.Status.ControlledObjects = append(a.Status.ControlledObjects, newObjectReference(obj))
+}
+
+type genericObjectSetPhaseList interface {
+	ClientObjectList() client.ObjectList
+	GetItems() []genericObjectSetPhase
+}
+
+type genericObjectSetPhaseListFactory func(
+	scheme *runtime.Scheme) genericObjectSetPhaseList
+
+var (
+	objectSetPhaseListGVK        = corev1alpha1.GroupVersion.WithKind("ObjectSetPhaseList")
+	clusterObjectSetPhaseListGVK = corev1alpha1.GroupVersion.WithKind("ClusterObjectSetPhaseList")
+)
+
+func newGenericObjectSetPhaseList(scheme *runtime.Scheme) genericObjectSetPhaseList {
+	obj, err := scheme.New(objectSetPhaseListGVK)
+	if err != nil {
+		panic(err)
+	}
+
+	return &GenericObjectSetPhaseList{
+		ObjectSetPhaseList: *obj.(*corev1alpha1.ObjectSetPhaseList)}
+}
+
+func newGenericClusterObjectSetPhaseList(scheme *runtime.Scheme) genericObjectSetPhaseList {
+	obj, err := scheme.New(clusterObjectSetPhaseListGVK)
+	if err != nil {
+		panic(err)
+	}
+
+	return &GenericClusterObjectSetPhaseList{
+		ClusterObjectSetPhaseList: *obj.(*corev1alpha1.ClusterObjectSetPhaseList)}
+}
+
+var (
+	_ genericObjectSetPhaseList = (*GenericObjectSetPhaseList)(nil)
+	_ genericObjectSetPhaseList = (*GenericClusterObjectSetPhaseList)(nil)
+)
+
+type GenericObjectSetPhaseList struct {
+	corev1alpha1.ObjectSetPhaseList
+}
+
+func (a *GenericObjectSetPhaseList) ClientObjectList() client.ObjectList {
+	return &a.ObjectSetPhaseList
+}
+
+func (a *GenericObjectSetPhaseList) GetItems() []genericObjectSetPhase {
+	out := make([]genericObjectSetPhase, len(a.Items))
+	for i := range a.Items {
+		out[i] = &GenericObjectSetPhase{
+			ObjectSetPhase: a.Items[i],
+		}
+	}
+	return out
+}
+
+type GenericClusterObjectSetPhaseList struct {
+	corev1alpha1.ClusterObjectSetPhaseList
+}
+
+func (a *GenericClusterObjectSetPhaseList) ClientObjectList() client.ObjectList {
+	return &a.ClusterObjectSetPhaseList
+}
+
+func (a *GenericClusterObjectSetPhaseList) GetItems() []genericObjectSetPhase {
+	out := make([]genericObjectSetPhase, len(a.Items))
+	for i := range a.Items {
+		out[i] = &GenericClusterObjectSetPhase{
+			ClusterObjectSetPhase: a.Items[i],
+		}
+	}
+	return out
+}
+
+func newObjectReference(obj client.Object) corev1alpha1.ObjectSetObjectReference {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return corev1alpha1.ObjectSetObjectReference{
+		Group:     gvk.Group,
+		Kind:      gvk.Kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+}
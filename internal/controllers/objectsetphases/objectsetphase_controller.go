@@ -0,0 +1,604 @@
+package objectsetphases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/probing"
+)
+
+// Class of ObjectSetPhases that the built-in controller
+// reconciles on the same cluster as the parent ObjectSet.
+const DefaultObjectSetPhaseClass = "default"
+
+// Generic reconciler for both ObjectSetPhase and ClusterObjectSetPhase objects.
+// Only ObjectSetPhases with a matching .spec.class are reconciled.
+type GenericObjectSetPhaseController struct {
+	newObjectSetPhase     genericObjectSetPhaseFactory
+	newObjectSetPhaseList genericObjectSetPhaseListFactory
+	newObjectSet          objectSetFactory
+
+	class    string
+	log      logr.Logger
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	// client to read and write ObjectSetPhase objects.
+	client client.Client
+
+	// default target cluster, used by phases without a target cluster reference.
+	// nil, if the controller has no default target cluster.
+	dynamicCache    dynamicCache
+	phaseReconciler phaseReconciler
+	// optional, reports the RemoteClusterReachable condition when set.
+	remoteClusterHealthChecker remoteClusterHealthChecker
+
+	ownerStrategy ownerStrategy
+	statusBatcher *controllers.StatusUpdateBatcher
+
+	// optional, connects to target clusters referenced by phases.
+	newTargetCluster   TargetClusterFactory
+	newPhaseReconciler phaseReconcilerFactory
+	targetClustersMux  sync.Mutex
+	// connections to target clusters by kubeconfig Secret.
+	targetClusters map[targetClusterKey]*targetClusterConnection
+	// set by SetupWithManager, watches of target clusters are added at runtime.
+	controller controller.Controller
+}
+
+type metricsRecorder interface {
+	RecordObjectDriftDetected(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectDriftReverted(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectConflict(owner client.Object, gvk schema.GroupVersionKind, manager string)
+}
+
+type remoteClusterHealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+type dynamicCache interface {
+	client.Reader
+	Source() source.Source
+	Free(ctx context.Context, obj client.Object) error
+	Watch(ctx context.Context, owner client.Object, obj runtime.Object) error
+}
+
+type ownerStrategy interface {
+	IsController(owner, obj metav1.Object) bool
+	HasController(obj metav1.Object) bool
+	ReleaseController(obj metav1.Object)
+	RemoveOwner(owner, obj metav1.Object)
+	SetControllerReference(owner, obj metav1.Object) error
+	EnqueueRequestForOwner(ownerType client.Object, isController bool) handler.EventHandler
+}
+
+type phaseReconciler interface {
+	ReconcilePhase(
+		ctx context.Context, owner controllers.PhaseObjectOwner,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+		probe probing.Prober, previous []client.Object,
+	) (failedProbes []string, err error)
+
+	DetectPhaseDrift(
+		ctx context.Context, owner controllers.PhaseObjectOwner,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+	) (drifted []string, err error)
+
+	TeardownPhase(
+		ctx context.Context, owner controllers.PhaseObjectOwner,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+	) (cleanupDone bool, err error)
+}
+
+// Creates a controller for ObjectSetPhases of the given class.
+// Objects of the phase are read from the dynamicCache and written using the targetWriter,
+// which may point to a different cluster than the given client.
+// targetReader must not be cached, it reads objects bypassing the dynamicCache.
+// remoteClusterHealthChecker may be nil, when objects are reconciled on the same cluster.
+// dynamicCache, targetWriter and targetReader may be nil,
+// if all phases of the class reference a target cluster.
+// newTargetCluster may be nil, if target cluster references are not supported.
+func NewObjectSetPhaseController(
+	log logr.Logger, scheme *runtime.Scheme,
+	dynamicCache dynamicCache, class string,
+	client client.Client, targetWriter client.Writer, targetReader client.Reader,
+	ownerStrategy ownerStrategy,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	remoteClusterHealthChecker remoteClusterHealthChecker,
+	kindPolicy controllers.KindPolicy, forceRemoveFinalizers bool,
+	settleDelays controllers.GroupKindDurations,
+	newTargetCluster TargetClusterFactory,
+) *GenericObjectSetPhaseController {
+	return newGenericObjectSetPhaseController(
+		newGenericObjectSetPhase,
+		newGenericObjectSetPhaseList,
+		newObjectSet,
+		log, scheme, dynamicCache, class,
+		client, targetWriter, targetReader, ownerStrategy,
+		metricsRecorder, recorder, remoteClusterHealthChecker,
+		kindPolicy, forceRemoveFinalizers, settleDelays, newTargetCluster,
+	)
+}
+
+// Creates a controller for ClusterObjectSetPhases of the given class.
+// Objects of the phase are read from the dynamicCache and written using the targetWriter,
+// which may point to a different cluster than the given client.
+// targetReader must not be cached, it reads objects bypassing the dynamicCache.
+// remoteClusterHealthChecker may be nil, when objects are reconciled on the same cluster.
+// dynamicCache, targetWriter and targetReader may be nil,
+// if all phases of the class reference a target cluster.
+// newTargetCluster may be nil, if target cluster references are not supported.
+func NewClusterObjectSetPhaseController(
+	log logr.Logger, scheme *runtime.Scheme,
+	dynamicCache dynamicCache, class string,
+	client client.Client, targetWriter client.Writer, targetReader client.Reader,
+	ownerStrategy ownerStrategy,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	remoteClusterHealthChecker remoteClusterHealthChecker,
+	kindPolicy controllers.KindPolicy, forceRemoveFinalizers bool,
+	settleDelays controllers.GroupKindDurations,
+	newTargetCluster TargetClusterFactory,
+) *GenericObjectSetPhaseController {
+	return newGenericObjectSetPhaseController(
+		newGenericClusterObjectSetPhase,
+		newGenericClusterObjectSetPhaseList,
+		newClusterObjectSet,
+		log, scheme, dynamicCache, class,
+		client, targetWriter, targetReader, ownerStrategy,
+		metricsRecorder, recorder, remoteClusterHealthChecker,
+		kindPolicy, forceRemoveFinalizers, settleDelays, newTargetCluster,
+	)
+}
+
+func newGenericObjectSetPhaseController(
+	newObjectSetPhase genericObjectSetPhaseFactory,
+	newObjectSetPhaseList genericObjectSetPhaseListFactory,
+	newObjectSet objectSetFactory,
+	log logr.Logger, scheme *runtime.Scheme,
+	dynamicCache dynamicCache, class string,
+	client client.Client, targetWriter client.Writer, targetReader client.Reader,
+	ownerStrategy ownerStrategy,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	remoteClusterHealthChecker remoteClusterHealthChecker,
+	kindPolicy controllers.KindPolicy, forceRemoveFinalizers bool,
+	settleDelays controllers.GroupKindDurations,
+	newTargetCluster TargetClusterFactory,
+) *GenericObjectSetPhaseController {
+	c := &GenericObjectSetPhaseController{
+		newObjectSetPhase:     newObjectSetPhase,
+		newObjectSetPhaseList: newObjectSetPhaseList,
+		newObjectSet:          newObjectSet,
+
+		class:    class,
+		log:      log,
+		scheme:   scheme,
+		recorder: recorder,
+		client:   client,
+
+		ownerStrategy: ownerStrategy,
+		statusBatcher: controllers.NewStatusUpdateBatcher(
+			controllers.DefaultStatusFlushInterval),
+
+		newTargetCluster: newTargetCluster,
+		newPhaseReconciler: newPhaseReconcilerFactory(
+			scheme, ownerStrategy, metricsRecorder,
+			kindPolicy, forceRemoveFinalizers, settleDelays),
+		targetClusters: map[targetClusterKey]*targetClusterConnection{},
+	}
+	if dynamicCache != nil {
+		c.dynamicCache = dynamicCache
+		c.phaseReconciler = c.newPhaseReconciler(targetWriter, targetReader, dynamicCache)
+		c.remoteClusterHealthChecker = remoteClusterHealthChecker
+	}
+	return c
+}
+
+// Creates phase reconcilers for the objects of a target cluster.
+type phaseReconcilerFactory func(
+	writer client.Writer, reader client.Reader, dynamicCache dynamicCache,
+) phaseReconciler
+
+func newPhaseReconcilerFactory(
+	scheme *runtime.Scheme, ownerStrategy ownerStrategy, metricsRecorder metricsRecorder,
+	kindPolicy controllers.KindPolicy, forceRemoveFinalizers bool,
+	settleDelays controllers.GroupKindDurations,
+) phaseReconcilerFactory {
+	return func(
+		writer client.Writer, reader client.Reader, dynamicCache dynamicCache,
+	) phaseReconciler {
+		return controllers.NewPhaseReconciler(
+			scheme, writer, reader, dynamicCache, ownerStrategy, metricsRecorder,
+			kindPolicy, forceRemoveFinalizers, settleDelays)
+	}
+}
+
+func (c *GenericObjectSetPhaseController) SetupWithManager(mgr ctrl.Manager) error {
+	objectSetPhase := c.newObjectSetPhase(c.scheme).ClientObject()
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(objectSetPhase, builder.WithPredicates(
+			predicate.NewPredicateFuncs(c.hasClass),
+		))
+	if c.dynamicCache != nil {
+		b = b.Watches(
+			c.dynamicCache.Source(),
+			c.ownerStrategy.EnqueueRequestForOwner(objectSetPhase, false),
+		)
+	}
+	controller, err := b.Build(c)
+	if err != nil {
+		return err
+	}
+	c.controller = controller
+	return nil
+}
+
+func (c *GenericObjectSetPhaseController) hasClass(obj client.Object) bool {
+	objectSetPhase := c.newObjectSetPhase(c.scheme)
+	if err := c.scheme.Convert(obj, objectSetPhase.ClientObject(), nil); err != nil {
+		return false
+	}
+	return objectSetPhase.GetClass() == c.class
+}
+
+func (c *GenericObjectSetPhaseController) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := c.log.WithValues("ObjectSetPhase", req.String())
+	defer log.Info("reconciled")
+	ctx = logr.NewContext(ctx, log)
+
+	objectSetPhase := c.newObjectSetPhase(c.scheme)
+	if err := c.client.Get(
+		ctx, req.NamespacedName, objectSetPhase.ClientObject()); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if objectSetPhase.GetClass() != c.class {
+		// Handled by another controller.
+		return ctrl.Result{}, nil
+	}
+
+	tearingDown := !objectSetPhase.ClientObject().GetDeletionTimestamp().IsZero() ||
+		objectSetPhase.IsArchived()
+
+	target, err := c.targetFor(ctx, objectSetPhase)
+	if err != nil {
+		if tearingDown {
+			// e.g. the kubeconfig Secret was deleted together with the phase.
+			return ctrl.Result{}, c.orphanObjects(ctx, objectSetPhase, nil, err)
+		}
+		return ctrl.Result{}, fmt.Errorf("resolving target cluster: %w", err)
+	}
+
+	original := objectSetPhase.ClientObject().DeepCopyObject().(client.Object)
+	oldConditions := make([]metav1.Condition, len(*objectSetPhase.GetConditions()))
+	copy(oldConditions, *objectSetPhase.GetConditions())
+
+	var res ctrl.Result
+	if target.remoteClusterHealthChecker != nil {
+		// Periodically re-check reachability of the target cluster.
+		res.RequeueAfter = remoteClusterHealthCheckInterval
+		if !c.reportRemoteClusterReachable(ctx, objectSetPhase, target.remoteClusterHealthChecker) {
+			if tearingDown {
+				return ctrl.Result{}, c.orphanObjects(
+					ctx, objectSetPhase, target.dynamicCache, errTargetClusterUnreachable)
+			}
+			return c.updateStatusAndRecordEvents(ctx, objectSetPhase, original, oldConditions, res)
+		}
+	}
+
+	if tearingDown {
+		return ctrl.Result{}, c.handleDeletionAndArchival(ctx, objectSetPhase, target)
+	}
+
+	if err := controllers.EnsureCachedFinalizer(ctx, c.client, objectSetPhase.ClientObject()); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	previous, err := c.lookupPreviousRevisions(ctx, objectSetPhase)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("lookup previous revisions: %w", err)
+	}
+
+	probe, err := probing.Parse(
+		ctx, objectSetPhase.GetAvailabilityProbes())
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("parsing probes: %w", err)
+	}
+
+	// Controlled objects are recorded again while reconciling.
+	objectSetPhase.SetControlledObjects(nil)
+	failedProbes, err := target.phaseReconciler.ReconcilePhase(
+		ctx, objectSetPhase, objectSetPhase.GetPhase(), probe, previous)
+	var settlingErr controllers.ObjectSettlingError
+	if errors.As(err, &settlingErr) {
+		// Reported like a failing probe, until the object has settled.
+		failedProbes = append(failedProbes, settlingErr.Error())
+		res = controllers.MergeResults(res, ctrl.Result{RequeueAfter: settlingErr.RequeueAfter})
+		err = nil
+	}
+	if err != nil {
+		if controllers.IsCollisionError(err) {
+			c.recorder.Event(objectSetPhase.ClientObject(), corev1.EventTypeWarning,
+				controllers.EventReasonCollisionDetected, err.Error())
+		}
+		var kindNotAllowedErr controllers.KindNotAllowedError
+		if errors.As(err, &kindNotAllowedErr) {
+			c.recorder.Event(objectSetPhase.ClientObject(), corev1.EventTypeWarning,
+				controllers.EventReasonKindNotAllowed, err.Error())
+		}
+		return ctrl.Result{}, err
+	}
+
+	var drifted []string
+	if objectSetPhase.IsPaused() {
+		drifted, err = target.phaseReconciler.DetectPhaseDrift(
+			ctx, objectSetPhase, objectSetPhase.GetPhase())
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("detecting drift: %w", err)
+		}
+	}
+	controllers.ReportDriftDetected(
+		objectSetPhase.GetConditions(), objectSetPhase.ClientObject().GetGeneration(),
+		objectSetPhase.IsPaused(), drifted)
+
+	if len(failedProbes) > 0 {
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ProbeFailure",
+			Message:            strings.Join(failedProbes, ", "),
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		if len(objectSetPhase.GetPhase().ExternalObjects) > 0 {
+			res = controllers.MergeResults(res, ctrl.Result{
+				RequeueAfter: controllers.ExternalObjectsRecheckInterval,
+			})
+		}
+	} else {
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Available",
+			Message:            "Object is available and passes all probes.",
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+	}
+
+	c.reportPausedCondition(objectSetPhase)
+	objectSetPhase.SetObservedGeneration(objectSetPhase.ClientObject().GetGeneration())
+	return c.updateStatusAndRecordEvents(ctx, objectSetPhase, original, oldConditions, res)
+}
+
+// Persists status changes and emits events for condition transitions.
+// Unchanged status is not written and writes shortly after a previous write are deferred,
+// so successive changes during a rollout are coalesced into fewer API calls.
+func (c *GenericObjectSetPhaseController) updateStatusAndRecordEvents(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+	original client.Object, oldConditions []metav1.Condition,
+	res ctrl.Result,
+) (ctrl.Result, error) {
+	if !controllers.StatusChanged(original, objectSetPhase.ClientObject()) {
+		return res, nil
+	}
+	if delay := c.statusBatcher.Delay(objectSetPhase.ClientObject()); delay > 0 {
+		return controllers.MergeResults(res, ctrl.Result{RequeueAfter: delay}), nil
+	}
+
+	if err := c.updateStatus(ctx, objectSetPhase, original); err != nil {
+		return res, err
+	}
+	c.statusBatcher.Written(objectSetPhase.ClientObject())
+	c.recordTransitionEvents(objectSetPhase, oldConditions)
+	return res, nil
+}
+
+// Emits events for condition transitions, after they have been persisted.
+func (c *GenericObjectSetPhaseController) recordTransitionEvents(
+	objectSetPhase genericObjectSetPhase, oldConditions []metav1.Condition,
+) {
+	obj := objectSetPhase.ClientObject()
+	newConditions := *objectSetPhase.GetConditions()
+
+	if cond, ok := controllers.ConditionTransition(
+		oldConditions, newConditions, corev1alpha1.ObjectSetAvailable); ok {
+		if cond.Status == metav1.ConditionTrue {
+			c.recorder.Event(obj, corev1.EventTypeNormal,
+				controllers.EventReasonPhaseAvailable, cond.Message)
+		} else if meta.IsStatusConditionTrue(oldConditions, corev1alpha1.ObjectSetAvailable) {
+			// Not being available yet during the first rollout is no reason to warn.
+			c.recorder.Event(obj, corev1.EventTypeWarning,
+				controllers.EventReasonNotAvailable, cond.Message)
+		}
+	}
+	if cond, ok := controllers.ConditionTransition(
+		oldConditions, newConditions, corev1alpha1.ObjectSetPaused); ok &&
+		cond.Status == metav1.ConditionTrue {
+		c.recorder.Event(obj, corev1.EventTypeNormal,
+			controllers.EventReasonPaused, cond.Message)
+	}
+}
+
+// Checks and reports whether the target cluster can be reached.
+// Returns false if the target cluster is unreachable.
+func (c *GenericObjectSetPhaseController) reportRemoteClusterReachable(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+	healthChecker remoteClusterHealthChecker,
+) bool {
+	if err := healthChecker.Check(ctx); err != nil {
+		logr.FromContextOrDiscard(ctx).Error(err, "remote cluster unreachable")
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetRemoteClusterReachable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "HealthCheckFailed",
+			Message:            err.Error(),
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		return false
+	}
+
+	meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+		Type:               corev1alpha1.ObjectSetRemoteClusterReachable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "HealthCheckSucceeded",
+		Message:            "Remote cluster API server is ready.",
+		ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+	})
+	return true
+}
+
+func (c *GenericObjectSetPhaseController) updateStatus(
+	ctx context.Context, objectSetPhase genericObjectSetPhase, original client.Object,
+) error {
+	// this controller owns status alone, so we can always update it without optimistic locking.
+	if err := c.client.Status().Patch(
+		ctx, objectSetPhase.ClientObject(), controllers.StatusPatch(original)); err != nil {
+		return fmt.Errorf("updating ObjectSetPhase status: %w", err)
+	}
+	return nil
+}
+
+func (c *GenericObjectSetPhaseController) reportPausedCondition(objectSetPhase genericObjectSetPhase) {
+	if objectSetPhase.IsPaused() {
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetPaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Paused",
+			Message:            "Lifecycle state set to paused.",
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+	} else {
+		meta.RemoveStatusCondition(objectSetPhase.GetConditions(), corev1alpha1.ObjectSetPaused)
+	}
+}
+
+func (c *GenericObjectSetPhaseController) handleDeletionAndArchival(
+	ctx context.Context, objectSetPhase genericObjectSetPhase, target target,
+) error {
+	done, err := target.phaseReconciler.TeardownPhase(
+		ctx, objectSetPhase, objectSetPhase.GetPhase())
+	if err != nil {
+		return fmt.Errorf("error tearing down during deletion: %w", err)
+	}
+	if !done {
+		// don't remove finalizer before deletion is done
+		return nil
+	}
+
+	return controllers.FreeCacheAndRemoveFinalizer(
+		ctx, c.client, objectSetPhase.ClientObject(), target.dynamicCache)
+}
+
+// Removes the finalizer of an ObjectSetPhase that is deleted or archived,
+// without tearing down its objects, because its target cluster is unavailable.
+// Otherwise deleting the phase would hang forever, when the target cluster is gone for good.
+// Objects are left behind on the target cluster.
+func (c *GenericObjectSetPhaseController) orphanObjects(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+	dynamicCache dynamicCache, reason error,
+) error {
+	obj := objectSetPhase.ClientObject()
+	if !controllerutil.ContainsFinalizer(obj, controllers.CachedFinalizer) {
+		return nil
+	}
+
+	logr.FromContextOrDiscard(ctx).Info("orphaning objects", "reason", reason.Error())
+	c.recorder.Event(obj, corev1.EventTypeWarning, controllers.EventReasonObjectsOrphaned,
+		fmt.Sprintf("Objects were not torn down, target cluster unavailable: %v", reason))
+
+	if dynamicCache != nil {
+		return controllers.FreeCacheAndRemoveFinalizer(ctx, c.client, obj, dynamicCache)
+	}
+	return controllers.RemoveFinalizer(ctx, c.client, obj, controllers.CachedFinalizer)
+}
+
+// Previous revisions are referenced by ObjectSet name.
+// Objects of those revisions are either controlled by the ObjectSet itself
+// or by one of the ObjectSetPhases that the ObjectSet delegated a phase to.
+// Phases of all classes are considered, so objects can be taken over,
+// when a phase is delegated to a different class in a later revision.
+func (c *GenericObjectSetPhaseController) lookupPreviousRevisions(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+) ([]client.Object, error) {
+	previousRefs := objectSetPhase.GetPrevious()
+	if len(previousRefs) == 0 {
+		return nil, nil
+	}
+
+	namespace := objectSetPhase.ClientObject().GetNamespace()
+	previousNames := map[string]struct{}{}
+	var previous []client.Object
+	for _, prev := range previousRefs {
+		previousNames[prev.Name] = struct{}{}
+
+		objectSet := c.newObjectSet(c.scheme)
+		if err := c.client.Get(ctx, client.ObjectKey{
+			Name: prev.Name, Namespace: namespace,
+		}, objectSet); err != nil {
+			return nil, err
+		}
+		previous = append(previous, objectSet)
+	}
+
+	objectSetPhaseList := c.newObjectSetPhaseList(c.scheme)
+	if err := c.client.List(
+		ctx, objectSetPhaseList.ClientObjectList(),
+		client.InNamespace(namespace),
+	); err != nil {
+		return nil, err
+	}
+	for _, item := range objectSetPhaseList.GetItems() {
+		controllerRef := metav1.GetControllerOf(item.ClientObject())
+		if controllerRef == nil {
+			continue
+		}
+		if _, ok := previousNames[controllerRef.Name]; ok {
+			previous = append(previous, item.ClientObject())
+		}
+	}
+	return previous, nil
+}
+
+type objectSetFactory func(scheme *runtime.Scheme) client.Object
+
+var (
+	objectSetGVK        = corev1alpha1.GroupVersion.WithKind("ObjectSet")
+	clusterObjectSetGVK = corev1alpha1.GroupVersion.WithKind("ClusterObjectSet")
+)
+
+func newObjectSet(scheme *runtime.Scheme) client.Object {
+	obj, err := scheme.New(objectSetGVK)
+	if err != nil {
+		panic(err)
+	}
+	return obj.(client.Object)
+}
+
+func newClusterObjectSet(scheme *runtime.Scheme) client.Object {
+	obj, err := scheme.New(clusterObjectSetGVK)
+	if err != nil {
+		panic(err)
+	}
+	return obj.(client.Object)
+}
@@ -0,0 +1,257 @@
+package objectsetphases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/probing"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+var testScheme = runtime.NewScheme()
+
+func init() {
+	if err := corev1alpha1.AddToScheme(testScheme); err != nil {
+		panic(err)
+	}
+}
+
+func TestGenericObjectSetPhaseController_Reconcile_clearsControlledObjects(t *testing.T) {
+	c := testutil.NewClient()
+	pr := &phaseReconcilerMock{}
+	controller := &GenericObjectSetPhaseController{
+		newObjectSetPhase: newGenericObjectSetPhase,
+		class:             DefaultObjectSetPhaseClass,
+		log:               logr.Discard(),
+		scheme:            testScheme,
+		recorder:          record.NewFakeRecorder(10),
+		client:            c,
+		phaseReconciler:   pr,
+		statusBatcher:     controllers.NewStatusUpdateBatcher(controllers.DefaultStatusFlushInterval),
+	}
+
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSetPhase")).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1alpha1.ObjectSetPhase)
+			*obj = corev1alpha1.ObjectSetPhase{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test", Namespace: "test", Generation: 1,
+					Finalizers: []string{controllers.CachedFinalizer},
+				},
+				Spec: corev1alpha1.ObjectSetPhaseSpec{
+					ObjectSetTemplatePhase: corev1alpha1.ObjectSetTemplatePhase{
+						Class: DefaultObjectSetPhaseClass,
+					},
+				},
+				Status: corev1alpha1.ObjectSetPhaseStatus{
+					ControlledObjects: []corev1alpha1.ObjectSetObjectReference{
+						{Kind: "ConfigMap", Name: "removed", Namespace: "test"},
+					},
+				},
+			}
+		}).
+		Return(nil)
+
+	var statusPatch []byte
+	c.StatusMock.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var err error
+			statusPatch, err = args.Get(2).(client.Patch).Data(args.Get(1).(client.Object))
+			require.NoError(t, err)
+		}).
+		Return(nil)
+	// The phase no longer controls any object.
+	pr.On("ReconcilePhase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]string(nil), nil)
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(statusPatch), `"controlledObjects":null`)
+}
+
+func TestGenericObjectSetPhaseController_Reconcile_orphansWhenTargetClusterGone(t *testing.T) {
+	c := testutil.NewClient()
+	recorder := record.NewFakeRecorder(10)
+	controller := &GenericObjectSetPhaseController{
+		newObjectSetPhase: newGenericObjectSetPhase,
+		class:             DefaultObjectSetPhaseClass,
+		log:               logr.Discard(),
+		scheme:            testScheme,
+		recorder:          recorder,
+		client:            c,
+		newTargetCluster: func([]byte) (*TargetCluster, error) {
+			return nil, errors.New("must not connect")
+		},
+		targetClusters: map[targetClusterKey]*targetClusterConnection{},
+	}
+
+	deletionTimestamp := metav1.Now()
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSetPhase")).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1alpha1.ObjectSetPhase)
+			*obj = corev1alpha1.ObjectSetPhase{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test", Namespace: "test",
+					DeletionTimestamp: &deletionTimestamp,
+					Finalizers:        []string{controllers.CachedFinalizer},
+				},
+				Spec: corev1alpha1.ObjectSetPhaseSpec{
+					ObjectSetTemplatePhase: corev1alpha1.ObjectSetTemplatePhase{
+						Class: DefaultObjectSetPhaseClass,
+						TargetCluster: &corev1alpha1.ObjectSetPhaseTargetCluster{
+							KubeconfigSecretRef: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+						},
+					},
+				},
+			}
+		}).
+		Return(nil)
+	// The kubeconfig Secret was deleted before the phase.
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1.Secret")).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "kubeconfig"))
+
+	var patch []byte
+	c.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var err error
+			patch, err = args.Get(2).(client.Patch).Data(args.Get(1).(client.Object))
+			require.NoError(t, err)
+		}).
+		Return(nil)
+
+	_, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(patch), `"finalizers":[]`)
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Contains(t, <-recorder.Events, controllers.EventReasonObjectsOrphaned)
+	}
+}
+
+func TestGenericObjectSetPhaseController_lookupPreviousRevisions(t *testing.T) {
+	c := testutil.NewClient()
+	controller := &GenericObjectSetPhaseController{
+		newObjectSetPhaseList: newGenericObjectSetPhaseList,
+		newObjectSet:          newObjectSet,
+		class:                 DefaultObjectSetPhaseClass,
+		scheme:                testScheme,
+		client:                c,
+	}
+
+	c.
+		On("Get", mock.Anything, client.ObjectKey{Name: "rev-1", Namespace: "test"},
+			mock.AnythingOfType("*v1alpha1.ObjectSet")).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*corev1alpha1.ObjectSet).Name = "rev-1"
+		}).
+		Return(nil)
+	newPhase := func(name, class, controller string) corev1alpha1.ObjectSetPhase {
+		phase := corev1alpha1.ObjectSetPhase{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		}
+		phase.Spec.Class = class
+		if len(controller) > 0 {
+			phase.OwnerReferences = []metav1.OwnerReference{
+				{Kind: "ObjectSet", Name: controller, Controller: pointer.Bool(true)},
+			}
+		}
+		return phase
+	}
+	c.
+		On("List", mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSetPhaseList"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*corev1alpha1.ObjectSetPhaseList).Items = []corev1alpha1.ObjectSetPhase{
+				newPhase("rev-1-default", DefaultObjectSetPhaseClass, "rev-1"),
+				newPhase("rev-1-other", "other", "rev-1"),
+				newPhase("rev-3-default", DefaultObjectSetPhaseClass, "rev-3"),
+				newPhase("orphan", DefaultObjectSetPhaseClass, ""),
+			}
+		}).
+		Return(nil)
+
+	objectSetPhase := &GenericObjectSetPhase{
+		ObjectSetPhase: corev1alpha1.ObjectSetPhase{
+			ObjectMeta: metav1.ObjectMeta{Name: "rev-2-default", Namespace: "test"},
+			Spec: corev1alpha1.ObjectSetPhaseSpec{
+				Previous: []corev1alpha1.PreviousRevisionReference{{Name: "rev-1"}},
+			},
+		},
+	}
+	previous, err := controller.lookupPreviousRevisions(context.Background(), objectSetPhase)
+	require.NoError(t, err)
+
+	// Objects can be taken over from phases of other classes.
+	var names []string
+	for _, prev := range previous {
+		names = append(names, prev.GetName())
+	}
+	assert.Equal(t, []string{"rev-1", "rev-1-default", "rev-1-other"}, names)
+}
+
+func TestGenericObjectSetPhaseController_recordTransitionEvents_firstReconcile(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	controller := &GenericObjectSetPhaseController{recorder: recorder}
+
+	objectSetPhase := &GenericObjectSetPhase{}
+	objectSetPhase.Status.Conditions = []metav1.Condition{
+		{Type: corev1alpha1.ObjectSetAvailable, Status: metav1.ConditionFalse},
+	}
+	controller.recordTransitionEvents(objectSetPhase, nil)
+
+	// Not being available yet is no reason to warn.
+	assert.Empty(t, recorder.Events)
+}
+
+type phaseReconcilerMock struct {
+	mock.Mock
+}
+
+func (m *phaseReconcilerMock) ReconcilePhase(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+	probe probing.Prober, previous []client.Object,
+) (failedProbes []string, err error) {
+	args := m.Called(ctx, owner, phase, probe, previous)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *phaseReconcilerMock) DetectPhaseDrift(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (drifted []string, err error) {
+	args := m.Called(ctx, owner, phase)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *phaseReconcilerMock) TeardownPhase(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (cleanupDone bool, err error) {
+	args := m.Called(ctx, owner, phase)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,38 @@
+package objectsetphases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// Interval in which reachability of the target cluster is re-checked.
+	remoteClusterHealthCheckInterval = 30 * time.Second
+	remoteClusterHealthCheckTimeout  = 10 * time.Second
+)
+
+// Checks reachability of a remote cluster via the /readyz endpoint of its API server.
+type RemoteClusterHealthChecker struct {
+	restClient rest.Interface
+}
+
+func NewRemoteClusterHealthChecker(cfg *rest.Config) (*RemoteClusterHealthChecker, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating discovery client: %w", err)
+	}
+	return &RemoteClusterHealthChecker{
+		restClient: dc.RESTClient(),
+	}, nil
+}
+
+func (c *RemoteClusterHealthChecker) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, remoteClusterHealthCheckTimeout)
+	defer cancel()
+
+	return c.restClient.Get().AbsPath("/readyz").Do(ctx).Error()
+}
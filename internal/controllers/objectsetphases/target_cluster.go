@@ -0,0 +1,169 @@
+package objectsetphases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// TargetCluster holds the clients of a cluster that objects of ObjectSetPhases are reconciled on.
+type TargetCluster struct {
+	// Cache of the objects reconciled on the target cluster.
+	DynamicCache dynamicCache
+	// Writes objects to the target cluster.
+	Writer client.Writer
+	// Reads objects from the target cluster, bypassing the DynamicCache.
+	Reader client.Reader
+	// Optional, reports the RemoteClusterReachable condition when set.
+	HealthChecker remoteClusterHealthChecker
+	// Optional, releases the connection when the target cluster is no longer used.
+	Stop func(ctx context.Context) error
+}
+
+// Connects to the cluster of the given kubeconfig.
+type TargetClusterFactory func(kubeconfig []byte) (*TargetCluster, error)
+
+var (
+	errTargetClusterNotSupported = errors.New(
+		"target clusters are not supported by the controller of this class")
+	errNoDefaultTargetCluster = errors.New(
+		"the controller of this class has no default target cluster, .spec.targetCluster is required")
+	errTargetClusterUnreachable = errors.New("target cluster unreachable")
+)
+
+// Clients to reconcile the objects of a phase with.
+type target struct {
+	dynamicCache               dynamicCache
+	phaseReconciler            phaseReconciler
+	remoteClusterHealthChecker remoteClusterHealthChecker
+}
+
+// Identifies the kubeconfig of a target cluster.
+type targetClusterKey struct {
+	secret  client.ObjectKey
+	dataKey string
+}
+
+// Connection to a target cluster, using the kubeconfig of a Secret.
+type targetClusterConnection struct {
+	// Serializes connecting, so slow clusters only block phases using the same Secret.
+	mux sync.Mutex
+	// Hash of the kubeconfig the connection was established with.
+	kubeconfigHash string
+	target         target
+	stop           func(ctx context.Context) error
+}
+
+// Returns the cluster to reconcile objects of the given ObjectSetPhase on.
+// Phases without a target cluster are reconciled on the default target of the controller.
+// Connections to target clusters are shared by all phases using the same kubeconfig Secret.
+// When the kubeconfig in the Secret changes, the previous connection is closed.
+func (c *GenericObjectSetPhaseController) targetFor(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+) (target, error) {
+	targetCluster := objectSetPhase.GetPhase().TargetCluster
+	if targetCluster == nil {
+		if c.phaseReconciler == nil {
+			return target{}, errNoDefaultTargetCluster
+		}
+		return target{
+			dynamicCache:               c.dynamicCache,
+			phaseReconciler:            c.phaseReconciler,
+			remoteClusterHealthChecker: c.remoteClusterHealthChecker,
+		}, nil
+	}
+	if c.newTargetCluster == nil {
+		return target{}, errTargetClusterNotSupported
+	}
+
+	key, kubeconfig, err := c.lookupKubeconfig(ctx, objectSetPhase, targetCluster.KubeconfigSecretRef)
+	if err != nil {
+		return target{}, err
+	}
+	sum := sha256.Sum256(kubeconfig)
+	kubeconfigHash := hex.EncodeToString(sum[:])
+
+	c.targetClustersMux.Lock()
+	conn, ok := c.targetClusters[key]
+	if !ok {
+		conn = &targetClusterConnection{}
+		c.targetClusters[key] = conn
+	}
+	c.targetClustersMux.Unlock()
+
+	conn.mux.Lock()
+	defer conn.mux.Unlock()
+	if conn.kubeconfigHash == kubeconfigHash {
+		return conn.target, nil
+	}
+
+	cluster, err := c.newTargetCluster(kubeconfig)
+	if err != nil {
+		return target{}, fmt.Errorf("connecting to target cluster: %w", err)
+	}
+	if c.controller != nil {
+		if err := c.controller.Watch(
+			cluster.DynamicCache.Source(),
+			c.ownerStrategy.EnqueueRequestForOwner(c.newObjectSetPhase(c.scheme).ClientObject(), false),
+		); err != nil {
+			return target{}, fmt.Errorf("watching target cluster: %w", err)
+		}
+	}
+	if conn.stop != nil {
+		// Credentials were rotated, the previous connection is no longer used.
+		if err := conn.stop(ctx); err != nil {
+			logr.FromContextOrDiscard(ctx).Error(err, "closing previous target cluster connection")
+		}
+	}
+	conn.kubeconfigHash = kubeconfigHash
+	conn.target = target{
+		dynamicCache:               cluster.DynamicCache,
+		phaseReconciler:            c.newPhaseReconciler(cluster.Writer, cluster.Reader, cluster.DynamicCache),
+		remoteClusterHealthChecker: cluster.HealthChecker,
+	}
+	conn.stop = cluster.Stop
+	return conn.target, nil
+}
+
+// Reads the kubeconfig referenced by the given ObjectSetPhase.
+// Namespaced phases may only reference Secrets in their own namespace.
+func (c *GenericObjectSetPhaseController) lookupKubeconfig(
+	ctx context.Context, objectSetPhase genericObjectSetPhase,
+	ref corev1alpha1.KubeconfigSecretReference,
+) (targetClusterKey, []byte, error) {
+	key := targetClusterKey{
+		secret:  client.ObjectKey{Name: ref.Name, Namespace: ref.Namespace},
+		dataKey: ref.Key,
+	}
+	if len(key.dataKey) == 0 {
+		key.dataKey = corev1alpha1.DefaultKubeconfigSecretKey
+	}
+	if namespace := objectSetPhase.ClientObject().GetNamespace(); len(namespace) > 0 {
+		if len(ref.Namespace) > 0 && ref.Namespace != namespace {
+			return key, nil, fmt.Errorf(
+				"kubeconfig Secret %s must be in the namespace of the ObjectSetPhase", key.secret)
+		}
+		key.secret.Namespace = namespace
+	} else if len(ref.Namespace) == 0 {
+		return key, nil, fmt.Errorf("kubeconfig Secret %q requires a namespace", ref.Name)
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.client.Get(ctx, key.secret, secret); err != nil {
+		return key, nil, fmt.Errorf("getting kubeconfig Secret: %w", err)
+	}
+	kubeconfig, ok := secret.Data[key.dataKey]
+	if !ok {
+		return key, nil, fmt.Errorf("kubeconfig Secret %s has no key %q", key.secret, key.dataKey)
+	}
+	return key, kubeconfig, nil
+}
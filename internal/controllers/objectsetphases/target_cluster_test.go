@@ -0,0 +1,217 @@
+package objectsetphases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func newTargetClusterTestObjectSetPhase(
+	namespace string, targetCluster *corev1alpha1.ObjectSetPhaseTargetCluster,
+) genericObjectSetPhase {
+	objectSetPhase := newGenericObjectSetPhase(testScheme)
+	if len(namespace) == 0 {
+		objectSetPhase = newGenericClusterObjectSetPhase(testScheme)
+	}
+	objectSetPhase.ClientObject().SetName("test")
+	objectSetPhase.ClientObject().SetNamespace(namespace)
+	switch v := objectSetPhase.(type) {
+	case *GenericObjectSetPhase:
+		v.Spec.TargetCluster = targetCluster
+	case *GenericClusterObjectSetPhase:
+		v.Spec.TargetCluster = targetCluster
+	}
+	return objectSetPhase
+}
+
+func TestGenericObjectSetPhaseController_targetFor_default(t *testing.T) {
+	pr := &phaseReconcilerMock{}
+	controller := &GenericObjectSetPhaseController{phaseReconciler: pr}
+
+	target, err := controller.targetFor(
+		context.Background(), newTargetClusterTestObjectSetPhase("test", nil))
+	require.NoError(t, err)
+	assert.Same(t, pr, target.phaseReconciler)
+
+	controller = &GenericObjectSetPhaseController{}
+	_, err = controller.targetFor(
+		context.Background(), newTargetClusterTestObjectSetPhase("test", nil))
+	assert.ErrorIs(t, err, errNoDefaultTargetCluster)
+}
+
+func TestGenericObjectSetPhaseController_targetFor_notSupported(t *testing.T) {
+	controller := &GenericObjectSetPhaseController{phaseReconciler: &phaseReconcilerMock{}}
+
+	_, err := controller.targetFor(context.Background(), newTargetClusterTestObjectSetPhase(
+		"test", &corev1alpha1.ObjectSetPhaseTargetCluster{
+			KubeconfigSecretRef: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+		}))
+	assert.ErrorIs(t, err, errTargetClusterNotSupported)
+}
+
+func TestGenericObjectSetPhaseController_targetFor_kubeconfigSecret(t *testing.T) {
+	kubeconfig := []byte("kubeconfig-content")
+	c := testutil.NewClient()
+	c.
+		On("Get", mock.Anything, client.ObjectKey{Name: "kubeconfig", Namespace: "test"},
+			mock.AnythingOfType("*v1.Secret")).
+		Run(func(args mock.Arguments) {
+			secret := args.Get(2).(*corev1.Secret)
+			*secret = corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "test"},
+				Data: map[string][]byte{
+					corev1alpha1.DefaultKubeconfigSecretKey: kubeconfig,
+				},
+			}
+		}).
+		Return(nil)
+
+	var (
+		connected [][]byte
+		stopped   []string
+	)
+	controller := &GenericObjectSetPhaseController{
+		client: c,
+		newTargetCluster: func(kubeconfig []byte) (*TargetCluster, error) {
+			connected = append(connected, kubeconfig)
+			return &TargetCluster{
+				Stop: func(context.Context) error {
+					stopped = append(stopped, string(kubeconfig))
+					return nil
+				},
+			}, nil
+		},
+		newPhaseReconciler: func(client.Writer, client.Reader, dynamicCache) phaseReconciler {
+			return &phaseReconcilerMock{}
+		},
+		targetClusters: map[targetClusterKey]*targetClusterConnection{},
+	}
+
+	objectSetPhase := newTargetClusterTestObjectSetPhase(
+		"test", &corev1alpha1.ObjectSetPhaseTargetCluster{
+			KubeconfigSecretRef: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+		})
+	first, err := controller.targetFor(context.Background(), objectSetPhase)
+	require.NoError(t, err)
+	second, err := controller.targetFor(context.Background(), objectSetPhase)
+	require.NoError(t, err)
+	// The connection is shared while the kubeconfig stays the same.
+	assert.Same(t, first.phaseReconciler, second.phaseReconciler)
+	assert.Equal(t, [][]byte{[]byte("kubeconfig-content")}, connected)
+	assert.Empty(t, stopped)
+
+	// Rotated credentials replace the previous connection.
+	kubeconfig = []byte("rotated-content")
+	third, err := controller.targetFor(context.Background(), objectSetPhase)
+	require.NoError(t, err)
+	assert.NotSame(t, first.phaseReconciler, third.phaseReconciler)
+	assert.Equal(t, [][]byte{[]byte("kubeconfig-content"), []byte("rotated-content")}, connected)
+	assert.Equal(t, []string{"kubeconfig-content"}, stopped)
+	assert.Len(t, controller.targetClusters, 1)
+}
+
+func TestGenericObjectSetPhaseController_targetFor_connectsOutsideGlobalLock(t *testing.T) {
+	c := testutil.NewClient()
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1.Secret")).
+		Run(func(args mock.Arguments) {
+			key := args.Get(1).(client.ObjectKey)
+			secret := args.Get(2).(*corev1.Secret)
+			*secret = corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Data: map[string][]byte{
+					corev1alpha1.DefaultKubeconfigSecretKey: []byte(key.Name),
+				},
+			}
+		}).
+		Return(nil)
+
+	slowConnecting := make(chan struct{})
+	releaseSlow := make(chan struct{})
+	controller := &GenericObjectSetPhaseController{
+		client: c,
+		newTargetCluster: func(kubeconfig []byte) (*TargetCluster, error) {
+			if string(kubeconfig) == "slow" {
+				close(slowConnecting)
+				<-releaseSlow
+			}
+			return &TargetCluster{}, nil
+		},
+		newPhaseReconciler: func(client.Writer, client.Reader, dynamicCache) phaseReconciler {
+			return &phaseReconcilerMock{}
+		},
+		targetClusters: map[targetClusterKey]*targetClusterConnection{},
+	}
+	newPhase := func(secretName string) genericObjectSetPhase {
+		return newTargetClusterTestObjectSetPhase(
+			"test", &corev1alpha1.ObjectSetPhaseTargetCluster{
+				KubeconfigSecretRef: corev1alpha1.KubeconfigSecretReference{Name: secretName},
+			})
+	}
+
+	slowDone := make(chan error)
+	go func() {
+		_, err := controller.targetFor(context.Background(), newPhase("slow"))
+		slowDone <- err
+	}()
+	<-slowConnecting
+
+	// Phases of other clusters are not blocked by a slow cluster.
+	_, err := controller.targetFor(context.Background(), newPhase("fast"))
+	require.NoError(t, err)
+
+	close(releaseSlow)
+	require.NoError(t, <-slowDone)
+}
+
+func TestGenericObjectSetPhaseController_lookupKubeconfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		ref       corev1alpha1.KubeconfigSecretReference
+		errMsg    string
+	}{
+		{
+			name:      "other namespace",
+			namespace: "test",
+			ref:       corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig", Namespace: "other"},
+			errMsg:    "must be in the namespace of the ObjectSetPhase",
+		},
+		{
+			name:   "cluster-scoped without namespace",
+			ref:    corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+			errMsg: "requires a namespace",
+		},
+		{
+			name:      "missing key",
+			namespace: "test",
+			ref:       corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig", Key: "config"},
+			errMsg:    `has no key "config"`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			c.
+				On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1.Secret")).
+				Return(nil)
+			controller := &GenericObjectSetPhaseController{client: c}
+
+			_, _, err := controller.lookupKubeconfig(context.Background(),
+				newTargetClusterTestObjectSetPhase(test.namespace, nil), test.ref)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+}
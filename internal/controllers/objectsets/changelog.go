@@ -0,0 +1,129 @@
+package objectsets
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+const (
+	// Maximum number of changed field paths reported per object.
+	changelogMaxFields = 10
+	// Maximum number of changes reported per revision, keeping the status of large revisions small.
+	changelogMaxChanges = 50
+)
+
+// Compares the objects of an ObjectSet to the objects of its previous revision.
+// Nothing is reported, if previous is nil, as every object would be added.
+func objectSetChanges(
+	objectSet, previous genericObjectSet,
+) ([]corev1alpha1.ObjectSetChange, error) {
+	if previous == nil {
+		return nil, nil
+	}
+	current, currentOrder, err := objectsByReference(objectSet)
+	if err != nil {
+		return nil, err
+	}
+	prev, prevOrder, err := objectsByReference(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []corev1alpha1.ObjectSetChange
+	for _, ref := range currentOrder {
+		prevObj, ok := prev[ref]
+		if !ok {
+			changes = append(changes, corev1alpha1.ObjectSetChange{
+				ObjectSetObjectReference: ref,
+				Action:                   corev1alpha1.ObjectSetChangeActionAdded,
+			})
+			continue
+		}
+		var fields []string
+		diffFields(prevObj.Object, current[ref].Object, "", &fields)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > changelogMaxFields {
+			fields = fields[:changelogMaxFields]
+		}
+		changes = append(changes, corev1alpha1.ObjectSetChange{
+			ObjectSetObjectReference: ref,
+			Action:                   corev1alpha1.ObjectSetChangeActionUpdated,
+			Fields:                   fields,
+		})
+	}
+	for _, ref := range prevOrder {
+		if _, ok := current[ref]; !ok {
+			changes = append(changes, corev1alpha1.ObjectSetChange{
+				ObjectSetObjectReference: ref,
+				Action:                   corev1alpha1.ObjectSetChangeActionRemoved,
+			})
+		}
+	}
+	if len(changes) > changelogMaxChanges {
+		changes = changes[:changelogMaxChanges]
+	}
+	return changes, nil
+}
+
+// Returns all objects of the ObjectSet by reference and the references in order of appearance.
+func objectsByReference(objectSet genericObjectSet) (
+	map[corev1alpha1.ObjectSetObjectReference]*unstructured.Unstructured,
+	[]corev1alpha1.ObjectSetObjectReference, error,
+) {
+	objects := map[corev1alpha1.ObjectSetObjectReference]*unstructured.Unstructured{}
+	var order []corev1alpha1.ObjectSetObjectReference
+	for _, phase := range objectSet.GetPhases() {
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return nil, nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			}
+			ref := newObjectReference(obj)
+			if _, ok := objects[ref]; !ok {
+				order = append(order, ref)
+			}
+			objects[ref] = obj
+		}
+	}
+	return objects, order, nil
+}
+
+// Appends the paths of all fields differing between a and b to paths.
+func diffFields(a, b map[string]interface{}, prefix string, paths *[]string) {
+	keys := map[string]struct{}{}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, k := range sortedKeys {
+		path := k
+		if len(prefix) > 0 {
+			path = prefix + "." + k
+		}
+		aMap, aIsMap := a[k].(map[string]interface{})
+		bMap, bIsMap := b[k].(map[string]interface{})
+		if aIsMap && bIsMap {
+			diffFields(aMap, bMap, path, paths)
+			continue
+		}
+		if !reflect.DeepEqual(a[k], b[k]) {
+			*paths = append(*paths, path)
+		}
+	}
+}
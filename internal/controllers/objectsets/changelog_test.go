@@ -0,0 +1,78 @@
+package objectsets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func Test_objectSetChanges(t *testing.T) {
+	newObjectSet := func(objects ...string) *GenericObjectSet {
+		phase := corev1alpha1.ObjectSetTemplatePhase{Name: "phase-1"}
+		for _, obj := range objects {
+			phase.Objects = append(phase.Objects, corev1alpha1.ObjectSetObject{
+				Object: runtime.RawExtension{Raw: []byte(obj)},
+			})
+		}
+		objectSet := &GenericObjectSet{}
+		objectSet.Spec.Phases = []corev1alpha1.ObjectSetTemplatePhase{phase}
+		return objectSet
+	}
+
+	previous := newObjectSet(
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"operator"},"spec":{"replicas":1}}`,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"},"data":{"a":"1"}}`,
+		`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"old"}}`,
+	)
+	current := newObjectSet(
+		`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"operator","labels":{"v":"2"}},"spec":{"replicas":2}}`,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"},"data":{"a":"1"}}`,
+		`{"apiVersion":"v1","kind":"Service","metadata":{"name":"new"}}`,
+	)
+
+	changes, err := objectSetChanges(current, previous)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1alpha1.ObjectSetChange{
+		{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Group: "apps", Kind: "Deployment", Name: "operator",
+			},
+			Action: corev1alpha1.ObjectSetChangeActionUpdated,
+			Fields: []string{"metadata.labels", "spec.replicas"},
+		},
+		{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Kind: "Service", Name: "new",
+			},
+			Action: corev1alpha1.ObjectSetChangeActionAdded,
+		},
+		{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Kind: "Secret", Name: "old",
+			},
+			Action: corev1alpha1.ObjectSetChangeActionRemoved,
+		},
+	}, changes)
+
+	t.Run("first revision", func(t *testing.T) {
+		changes, err := objectSetChanges(previous, nil)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		var objects []string
+		for i := 0; i < changelogMaxChanges+10; i++ {
+			objects = append(objects, fmt.Sprintf(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-%d"}}`, i))
+		}
+		changes, err := objectSetChanges(newObjectSet(objects...), newObjectSet())
+		require.NoError(t, err)
+		assert.Len(t, changes, changelogMaxChanges)
+	})
+}
@@ -20,6 +20,21 @@ type genericObjectSet interface {
 	GetAvailabilityProbes() []corev1alpha1.ObjectSetProbe
 	SetStatusRevision(revision int64)
 	GetStatusRevision() int64
+	GetStatusCollection() []corev1alpha1.ObjectSetStatusCollection
+	SetCollectedStatus(collected map[string]string)
+	GetTeardownStatus() *corev1alpha1.ObjectSetTeardownStatus
+	SetTeardownStatus(teardown *corev1alpha1.ObjectSetTeardownStatus)
+	GetOrphanedObjects() []corev1alpha1.ObjectSetObjectReference
+	SetOrphanedObjects(orphaned []corev1alpha1.ObjectSetObjectReference)
+	SetStuckObjects(stuck []corev1alpha1.ObjectSetStuckObject)
+	SetChanges(changes []corev1alpha1.ObjectSetChange)
+	SetConflicts(conflicts []corev1alpha1.ObjectSetConflict)
+	RecordConflict(conflict corev1alpha1.ObjectSetConflict)
+	GetAdoptObjectsSelector() *metav1.LabelSelector
+	SetAdoptionCandidates(candidates []corev1alpha1.ObjectSetObjectReference)
+	SetManagedObjects(managed []corev1alpha1.ObjectSetManagedObject)
+	RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject)
+	SetObservedGeneration(generation int64)
 }
 
 type genericObjectSetFactory func(
@@ -118,6 +133,10 @@ func (a *GenericObjectSet) GetAvailabilityProbes() []corev1alpha1.ObjectSetProbe
 	return a.Spec.AvailabilityProbes
 }
 
+func (a *GenericObjectSet) GetServiceAccountName() string {
+	return a.Spec.ServiceAccountName
+}
+
 func (a *GenericObjectSet) SetStatusRevision(revision int64) {
 	a.Status.Revision = revision
 }
@@ -126,6 +145,74 @@ func (a *GenericObjectSet) GetStatusRevision() int64 {
 	return a.Status.Revision
 }
 
+func (a *GenericObjectSet) SetObservedGeneration(generation int64) {
+	a.Status.ObservedGeneration = generation
+}
+
+func (a *GenericObjectSet) GetStatusCollection() []corev1alpha1.ObjectSetStatusCollection {
+	return a.Spec.StatusCollection
+}
+
+func (a *GenericObjectSet) SetCollectedStatus(collected map[string]string) {
+	a.Status.CollectedStatus = collected
+}
+
+func (a *GenericObjectSet) GetTeardownStatus() *corev1alpha1.ObjectSetTeardownStatus {
+	return a.Status.Teardown
+}
+
+func (a *GenericObjectSet) SetTeardownStatus(teardown *corev1alpha1.ObjectSetTeardownStatus) {
+	a.Status.Teardown = teardown
+}
+
+func (a *GenericObjectSet) GetOrphanedObjects() []corev1alpha1.ObjectSetObjectReference {
+	return a.Status.OrphanedObjects
+}
+
+func (a *GenericObjectSet) SetOrphanedObjects(orphaned []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.OrphanedObjects = orphaned
+}
+
+func (a *GenericObjectSet) RecordOrphanedObject(obj client.Object) {
+	a.Status.OrphanedObjects = appendObjectReference(a.Status.OrphanedObjects, obj)
+}
+
+func (a *GenericObjectSet) SetStuckObjects(stuck []corev1alpha1.ObjectSetStuckObject) {
+	a.Status.StuckObjects = stuck
+}
+
+func (a *GenericObjectSet) RecordStuckObject(obj client.Object) {
+	a.Status.StuckObjects = append(a.Status.StuckObjects, newStuckObject(obj))
+}
+
+func (a *GenericObjectSet) SetChanges(changes []corev1alpha1.ObjectSetChange) {
+	a.Status.Changes = changes
+}
+
+func (a *GenericObjectSet) SetConflicts(conflicts []corev1alpha1.ObjectSetConflict) {
+	a.Status.Conflicts = conflicts
+}
+
+func (a *GenericObjectSet) RecordConflict(conflict corev1alpha1.ObjectSetConflict) {
+	a.Status.Conflicts = append(a.Status.Conflicts, conflict)
+}
+
+func (a *GenericObjectSet) GetAdoptObjectsSelector() *metav1.LabelSelector {
+	return a.Spec.AdoptObjects
+}
+
+func (a *GenericObjectSet) SetAdoptionCandidates(candidates []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.AdoptionCandidates = candidates
+}
+
+func (a *GenericObjectSet) SetManagedObjects(managed []corev1alpha1.ObjectSetManagedObject) {
+	a.Status.ManagedObjects = managed
+}
+
+func (a *GenericObjectSet) RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject) {
+	a.Status.ManagedObjects = upsertManagedObject(a.Status.ManagedObjects, managed)
+}
+
 type GenericClusterObjectSet struct {
 	corev1alpha1.ClusterObjectSet
 }
@@ -196,3 +283,127 @@ func (a *GenericClusterObjectSet) SetStatusRevision(revision int64) {
 func (a *GenericClusterObjectSet) GetStatusRevision() int64 {
 	return a.Status.Revision
 }
+
+func (a *GenericClusterObjectSet) SetObservedGeneration(generation int64) {
+	a.Status.ObservedGeneration = generation
+}
+
+func (a *GenericClusterObjectSet) GetStatusCollection() []corev1alpha1.ObjectSetStatusCollection {
+	return a.Spec.StatusCollection
+}
+
+func (a *GenericClusterObjectSet) SetCollectedStatus(collected map[string]string) {
+	a.Status.CollectedStatus = collected
+}
+
+func (a *GenericClusterObjectSet) GetTeardownStatus() *corev1alpha1.ObjectSetTeardownStatus {
+	return a.Status.Teardown
+}
+
+func (a *GenericClusterObjectSet) SetTeardownStatus(teardown *corev1alpha1.ObjectSetTeardownStatus) {
+	a.Status.Teardown = teardown
+}
+
+func (a *GenericClusterObjectSet) GetOrphanedObjects() []corev1alpha1.ObjectSetObjectReference {
+	return a.Status.OrphanedObjects
+}
+
+func (a *GenericClusterObjectSet) SetOrphanedObjects(orphaned []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.OrphanedObjects = orphaned
+}
+
+func (a *GenericClusterObjectSet) RecordOrphanedObject(obj client.Object) {
+	a.Status.OrphanedObjects = appendObjectReference(a.Status.OrphanedObjects, obj)
+}
+
+func (a *GenericClusterObjectSet) SetStuckObjects(stuck []corev1alpha1.ObjectSetStuckObject) {
+	a.Status.StuckObjects = stuck
+}
+
+func (a *GenericClusterObjectSet) RecordStuckObject(obj client.Object) {
+	a.Status.StuckObjects = append(a.Status.StuckObjects, newStuckObject(obj))
+}
+
+func (a *GenericClusterObjectSet) SetChanges(changes []corev1alpha1.ObjectSetChange) {
+	a.Status.Changes = changes
+}
+
+func (a *GenericClusterObjectSet) SetConflicts(conflicts []corev1alpha1.ObjectSetConflict) {
+	a.Status.Conflicts = conflicts
+}
+
+func (a *GenericClusterObjectSet) RecordConflict(conflict corev1alpha1.ObjectSetConflict) {
+	a.Status.Conflicts = append(a.Status.Conflicts, conflict)
+}
+
+func (a *GenericClusterObjectSet) GetAdoptObjectsSelector() *metav1.LabelSelector {
+	return a.Spec.AdoptObjects
+}
+
+func (a *GenericClusterObjectSet) SetAdoptionCandidates(candidates []corev1alpha1.ObjectSetObjectReference) {
+	a.Status.AdoptionCandidates = candidates
+}
+
+func (a *GenericClusterObjectSet) SetManagedObjects(managed []corev1alpha1.ObjectSetManagedObject) {
+	a.Status.ManagedObjects = managed
+}
+
+func (a *GenericClusterObjectSet) RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject) {
+	a.Status.ManagedObjects = upsertManagedObject(a.Status.ManagedObjects, managed)
+}
+
+// Appends a reference to obj, if not already present.
+func appendObjectReference(
+	refs []corev1alpha1.ObjectSetObjectReference, obj client.Object,
+) []corev1alpha1.ObjectSetObjectReference {
+	ref := newObjectReference(obj)
+	for _, r := range refs {
+		if r == ref {
+			return refs
+		}
+	}
+	return append(refs, ref)
+}
+
+// Maximum number of objects reported in .status.managedObjects,
+// keeping the status of large revisions small.
+const maxManagedObjects = 100
+
+// Replaces the entry for the same object or appends a new one,
+// so entries of objects not reconciled this time are kept.
+// New objects are dropped, once maxManagedObjects are reported.
+func upsertManagedObject(
+	managed []corev1alpha1.ObjectSetManagedObject, obj corev1alpha1.ObjectSetManagedObject,
+) []corev1alpha1.ObjectSetManagedObject {
+	for i := range managed {
+		if managed[i].ObjectSetObjectReference == obj.ObjectSetObjectReference {
+			managed[i] = obj
+			return managed
+		}
+	}
+	if len(managed) >= maxManagedObjects {
+		return managed
+	}
+	return append(managed, obj)
+}
+
+func newObjectReference(obj client.Object) corev1alpha1.ObjectSetObjectReference {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	return corev1alpha1.ObjectSetObjectReference{
+		Group:     gvk.Group,
+		Kind:      gvk.Kind,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+}
+
+func newStuckObject(obj client.Object) corev1alpha1.ObjectSetStuckObject {
+	stuck := corev1alpha1.ObjectSetStuckObject{
+		ObjectSetObjectReference: newObjectReference(obj),
+		Finalizers:               obj.GetFinalizers(),
+	}
+	if deletionTimestamp := obj.GetDeletionTimestamp(); deletionTimestamp != nil {
+		stuck.DeletionTimestamp = *deletionTimestamp
+	}
+	return stuck
+}
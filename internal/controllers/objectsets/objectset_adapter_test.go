@@ -0,0 +1,53 @@
+package objectsets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func TestGenericObjectSet_RecordManagedObject(t *testing.T) {
+	newManaged := func(name string, available bool) corev1alpha1.ObjectSetManagedObject {
+		return corev1alpha1.ObjectSetManagedObject{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Kind: "ConfigMap", Name: name, Namespace: "test",
+			},
+			Version:   "v1",
+			Available: available,
+		}
+	}
+
+	objectSet := &GenericObjectSet{}
+	objectSet.RecordManagedObject(newManaged("a", false))
+	objectSet.RecordManagedObject(newManaged("b", true))
+	objectSet.RecordManagedObject(newManaged("a", true))
+
+	assert.Equal(t, []corev1alpha1.ObjectSetManagedObject{
+		newManaged("a", true),
+		newManaged("b", true),
+	}, objectSet.Status.ManagedObjects)
+}
+
+func TestGenericObjectSet_RecordManagedObject_limited(t *testing.T) {
+	objectSet := &GenericObjectSet{}
+	for i := 0; i < maxManagedObjects+10; i++ {
+		objectSet.RecordManagedObject(corev1alpha1.ObjectSetManagedObject{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Kind: "ConfigMap", Name: fmt.Sprintf("cm-%d", i), Namespace: "test",
+			},
+		})
+	}
+	assert.Len(t, objectSet.Status.ManagedObjects, maxManagedObjects)
+
+	// Objects already reported are still updated.
+	objectSet.RecordManagedObject(corev1alpha1.ObjectSetManagedObject{
+		ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+			Kind: "ConfigMap", Name: "cm-0", Namespace: "test",
+		},
+		Available: true,
+	})
+	assert.True(t, objectSet.Status.ManagedObjects[0].Available)
+}
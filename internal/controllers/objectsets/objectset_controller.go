@@ -2,12 +2,17 @@ package objectsets
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -23,13 +28,20 @@ type GenericObjectSetController struct {
 	newObjectSet      genericObjectSetFactory
 	newObjectSetPhase genericObjectSetPhaseFactory
 
-	client     client.Client
-	log        logr.Logger
-	scheme     *runtime.Scheme
+	client          client.Client
+	log             logr.Logger
+	scheme          *runtime.Scheme
+	recorder        record.EventRecorder
+	metricsRecorder metricsRecorder
+	// Run before any reconciler, a requeue stops the reconciliation,
+	// e.g. nothing is applied while permissions are missing.
+	preconditions []reconciler
+	// Results are merged, so status is still reported while rolling out.
 	reconciler []reconciler
 
 	dynamicCache    dynamicCache
 	teardownHandler teardownHandler
+	statusBatcher   *controllers.StatusUpdateBatcher
 }
 
 type reconciler interface {
@@ -43,63 +55,107 @@ type dynamicCache interface {
 	Watch(ctx context.Context, owner client.Object, obj runtime.Object) error
 }
 
+type metricsRecorder interface {
+	RecordObjectDriftDetected(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectDriftReverted(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectConflict(owner client.Object, gvk schema.GroupVersionKind, manager string)
+	RecordObjectSetUnavailableSince(objectSet client.Object, since *time.Time)
+	RecordObjectSetPreviousRevisionMissing(objectSet client.Object, missing bool)
+}
+
 type teardownHandler interface {
 	Teardown(
 		ctx context.Context, objectSet genericObjectSet,
-	) (cleanupDone bool, err error)
+	) (cleanupDone bool, requeueAfter time.Duration, err error)
 }
 
+// Creates a new ObjectSet controller.
+// tenantIsolation restricts ObjectSets in all namespaces to objects within their own namespace.
 func NewObjectSetController(
-	c client.Client, log logr.Logger,
+	c client.Client, uncachedClient client.Reader, log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicCache,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	tenantIsolation bool, kindPolicy controllers.KindPolicy,
+	forceRemoveFinalizers bool, settleDelays controllers.GroupKindDurations,
 ) *GenericObjectSetController {
 	return newGenericObjectSetController(
 		newGenericObjectSet,
 		newGenericObjectSetPhase,
-		c, log, scheme, dw,
+		c, uncachedClient, log, scheme, dw, metricsRecorder, recorder,
+		tenantIsolation, kindPolicy, forceRemoveFinalizers, settleDelays,
 	)
 }
 
 func NewClusterObjectSetController(
-	c client.Client, log logr.Logger,
+	c client.Client, uncachedClient client.Reader, log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicCache,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	kindPolicy controllers.KindPolicy, forceRemoveFinalizers bool,
+	settleDelays controllers.GroupKindDurations,
 ) *GenericObjectSetController {
 	return newGenericObjectSetController(
 		newGenericClusterObjectSet,
 		newGenericClusterObjectSetPhase,
-		c, log, scheme, dw,
+		c, uncachedClient, log, scheme, dw, metricsRecorder, recorder,
+		false, kindPolicy, forceRemoveFinalizers, settleDelays,
 	)
 }
 
 func newGenericObjectSetController(
 	newObjectSet genericObjectSetFactory,
 	newObjectSetPhase genericObjectSetPhaseFactory,
-	c client.Client, log logr.Logger,
+	c client.Client, uncachedClient client.Reader, log logr.Logger,
 	scheme *runtime.Scheme, dynamicCache dynamicCache,
+	metricsRecorder metricsRecorder, recorder record.EventRecorder,
+	tenantIsolation bool, kindPolicy controllers.KindPolicy,
+	forceRemoveFinalizers bool, settleDelays controllers.GroupKindDurations,
 ) *GenericObjectSetController {
 	controller := &GenericObjectSetController{
 		newObjectSet:      newObjectSet,
 		newObjectSetPhase: newObjectSetPhase,
 
-		client:       c,
-		log:          log,
-		scheme:       scheme,
-		dynamicCache: dynamicCache,
+		client:          c,
+		log:             log,
+		scheme:          scheme,
+		recorder:        recorder,
+		metricsRecorder: metricsRecorder,
+		dynamicCache:    dynamicCache,
+
+		statusBatcher: controllers.NewStatusUpdateBatcher(
+			controllers.DefaultStatusFlushInterval),
 	}
 
 	phasesReconciler := newPhasesReconciler(c, controllers.NewPhaseReconciler(
-		scheme, c, dynamicCache, ownerhandling.NewNative(scheme),
-	), scheme, newObjectSet)
+		scheme, c, uncachedClient, dynamicCache, ownerhandling.NewNative(scheme),
+		metricsRecorder, kindPolicy, forceRemoveFinalizers, settleDelays,
+	), newObjectSetRemotePhaseReconciler(
+		c, scheme, newObjectSetPhase,
+	), scheme, newObjectSet, newObjectSetPhase)
 
 	controller.teardownHandler = phasesReconciler
 
-	controller.reconciler = []reconciler{
+	controller.preconditions = []reconciler{
 		&revisionReconciler{
 			scheme:       scheme,
 			client:       c,
 			newObjectSet: newObjectSet,
 		},
+		&tenantIsolationReconciler{
+			client:     c,
+			restMapper: c.RESTMapper(),
+			enforced:   tenantIsolation,
+		},
+		&preflightReconciler{
+			client:         c,
+			uncachedReader: uncachedClient,
+			restMapper:     c.RESTMapper(),
+		},
+	}
+	controller.reconciler = []reconciler{
 		phasesReconciler,
+		&statusCollectionReconciler{
+			dynamicCache: dynamicCache,
+		},
 	}
 
 	return controller
@@ -137,42 +193,165 @@ func (c *GenericObjectSetController) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
+	original := objectSet.ClientObject().DeepCopyObject().(client.Object)
+	oldConditions := make([]metav1.Condition, len(*objectSet.GetConditions()))
+	copy(oldConditions, *objectSet.GetConditions())
+
 	if !objectSet.ClientObject().GetDeletionTimestamp().IsZero() ||
 		objectSet.IsArchived() {
-		if err := c.handleDeletionAndArchival(ctx, objectSet); err != nil {
-			return ctrl.Result{}, err
+		c.metricsRecorder.RecordObjectSetUnavailableSince(objectSet.ClientObject(), nil)
+		c.metricsRecorder.RecordObjectSetPreviousRevisionMissing(objectSet.ClientObject(), false)
+		res, err := c.handleDeletionAndArchival(ctx, objectSet)
+		if err != nil {
+			return res, err
 		}
 
-		return ctrl.Result{}, c.updateStatus(ctx, objectSet)
+		objectSet.SetObservedGeneration(objectSet.ClientObject().GetGeneration())
+		return c.updateStatusAndRecordEvents(ctx, objectSet, original, oldConditions, res)
 	}
 
 	if err := controllers.EnsureCachedFinalizer(ctx, c.client, objectSet.ClientObject()); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	var (
-		res ctrl.Result
-		err error
-	)
-	for _, r := range c.reconciler {
-		res, err = r.Reconcile(ctx, objectSet)
-		if err != nil || !res.IsZero() {
-			break
-		}
-	}
+	res, err := c.runReconcilers(ctx, objectSet)
+	c.recordMetrics(objectSet, err)
 	if err != nil {
+		c.recordErrorEvents(objectSet, err)
+		if controllers.IsCollisionError(err) {
+			// Persist conflict diagnostics, while adoption is retried with backoff.
+			if updateErr := c.updateStatus(ctx, objectSet, original); updateErr != nil {
+				return res, updateErr
+			}
+		}
 		return res, err
 	}
 
 	c.reportPausedCondition(ctx, objectSet)
-	return res, c.updateStatus(ctx, objectSet)
+	c.reportUpgradeableCondition(objectSet)
+	objectSet.SetObservedGeneration(objectSet.ClientObject().GetGeneration())
+	return c.updateStatusAndRecordEvents(ctx, objectSet, original, oldConditions, res)
 }
 
-func (c *GenericObjectSetController) updateStatus(ctx context.Context, objectSet genericObjectSet) error {
+func (c *GenericObjectSetController) runReconcilers(
+	ctx context.Context, objectSet genericObjectSet,
+) (res ctrl.Result, err error) {
+	for _, r := range c.preconditions {
+		if res, err = r.Reconcile(ctx, objectSet); err != nil || !res.IsZero() {
+			return res, err
+		}
+	}
+	for _, r := range c.reconciler {
+		rres, err := r.Reconcile(ctx, objectSet)
+		res = controllers.MergeResults(res, rres)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// Persists status changes and emits events for condition transitions.
+// Unchanged status is not written and writes shortly after a previous write are deferred,
+// so successive changes during a rollout are coalesced into fewer API calls.
+func (c *GenericObjectSetController) updateStatusAndRecordEvents(
+	ctx context.Context, objectSet genericObjectSet,
+	original client.Object, oldConditions []metav1.Condition,
+	res ctrl.Result,
+) (ctrl.Result, error) {
 	objectSet.UpdateStatusPhase()
+	if !controllers.StatusChanged(original, objectSet.ClientObject()) {
+		return res, nil
+	}
+	if delay := c.statusBatcher.Delay(objectSet.ClientObject()); delay > 0 {
+		return controllers.MergeResults(res, ctrl.Result{RequeueAfter: delay}), nil
+	}
+
+	if err := c.updateStatus(ctx, objectSet, original); err != nil {
+		return res, err
+	}
+	c.statusBatcher.Written(objectSet.ClientObject())
+	c.recordTransitionEvents(objectSet, oldConditions)
+	return res, nil
+}
+
+// Emits events for condition transitions, after they have been persisted.
+func (c *GenericObjectSetController) recordTransitionEvents(
+	objectSet genericObjectSet, oldConditions []metav1.Condition,
+) {
+	obj := objectSet.ClientObject()
+	newConditions := *objectSet.GetConditions()
+
+	if cond, ok := controllers.ConditionTransition(
+		oldConditions, newConditions, corev1alpha1.ObjectSetAvailable); ok {
+		if cond.Status == metav1.ConditionTrue {
+			c.recorder.Event(obj, corev1.EventTypeNormal,
+				controllers.EventReasonAvailable, cond.Message)
+		} else if meta.IsStatusConditionTrue(oldConditions, corev1alpha1.ObjectSetAvailable) {
+			// Not being available yet during the first rollout is no reason to warn.
+			c.recorder.Event(obj, corev1.EventTypeWarning,
+				controllers.EventReasonNotAvailable, cond.Message)
+		}
+	}
+	if cond, ok := controllers.ConditionTransition(
+		oldConditions, newConditions, corev1alpha1.ObjectSetPaused); ok &&
+		cond.Status == metav1.ConditionTrue {
+		c.recorder.Event(obj, corev1.EventTypeNormal,
+			controllers.EventReasonPaused, cond.Message)
+	}
+	if cond, ok := controllers.ConditionTransition(
+		oldConditions, newConditions, corev1alpha1.ObjectSetArchived); ok &&
+		cond.Status == metav1.ConditionTrue {
+		c.recorder.Event(obj, corev1.EventTypeNormal,
+			controllers.EventReasonArchived, "All objects have been cleaned up.")
+	}
+}
+
+func (c *GenericObjectSetController) recordErrorEvents(
+	objectSet genericObjectSet, err error,
+) {
+	var (
+		phaseNotOwnedErr  ObjectSetPhaseNotOwnedError
+		kindNotAllowedErr controllers.KindNotAllowedError
+	)
+	if controllers.IsCollisionError(err) || errors.As(err, &phaseNotOwnedErr) {
+		c.recorder.Event(objectSet.ClientObject(), corev1.EventTypeWarning,
+			controllers.EventReasonCollisionDetected, err.Error())
+	}
+	if errors.As(err, &kindNotAllowedErr) {
+		c.recorder.Event(objectSet.ClientObject(), corev1.EventTypeWarning,
+			controllers.EventReasonKindNotAllowed, err.Error())
+	}
+}
+
+// Updates the ObjectSet gauges, which serve as inputs for alerting and upgrade gating.
+func (c *GenericObjectSetController) recordMetrics(
+	objectSet genericObjectSet, err error,
+) {
+	obj := objectSet.ClientObject()
+	var prevNotFoundErr PreviousRevisionNotFoundError
+	c.metricsRecorder.RecordObjectSetPreviousRevisionMissing(
+		obj, errors.As(err, &prevNotFoundErr))
+
+	availableCond := meta.FindStatusCondition(
+		*objectSet.GetConditions(), corev1alpha1.ObjectSetAvailable)
+	if availableCond != nil && availableCond.Status == metav1.ConditionTrue {
+		c.metricsRecorder.RecordObjectSetUnavailableSince(obj, nil)
+		return
+	}
+	since := obj.GetCreationTimestamp().Time
+	if availableCond != nil {
+		since = availableCond.LastTransitionTime.Time
+	}
+	c.metricsRecorder.RecordObjectSetUnavailableSince(obj, &since)
+}
+
+func (c *GenericObjectSetController) updateStatus(
+	ctx context.Context, objectSet genericObjectSet, original client.Object,
+) error {
 	// this controller owns status alone, so we can always update it without optimistic locking.
-	objectSet.ClientObject().SetResourceVersion("")
-	if err := c.client.Status().Patch(ctx, objectSet.ClientObject(), client.Merge); err != nil {
+	if err := c.client.Status().Patch(
+		ctx, objectSet.ClientObject(), controllers.StatusPatch(original)); err != nil {
 		return fmt.Errorf("updating ObjectSet status: %w", err)
 	}
 	return nil
@@ -181,48 +360,93 @@ func (c *GenericObjectSetController) updateStatus(ctx context.Context, objectSet
 func (c *GenericObjectSetController) reportPausedCondition(ctx context.Context, objectSet genericObjectSet) {
 	if objectSet.IsPaused() {
 		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
-			Type:    corev1alpha1.ObjectSetPaused,
-			Status:  metav1.ConditionTrue,
-			Reason:  "Paused",
-			Message: "Lifecycle state set to paused.",
+			Type:               corev1alpha1.ObjectSetPaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Paused",
+			Message:            "Lifecycle state set to paused.",
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
 		})
 	} else {
 		meta.RemoveStatusCondition(objectSet.GetConditions(), corev1alpha1.ObjectSetPaused)
 	}
 }
 
+// Reports whether it is safe to upgrade to a new revision,
+// which is not the case while objects are still rolled out or unavailable.
+func (c *GenericObjectSetController) reportUpgradeableCondition(objectSet genericObjectSet) {
+	cond := metav1.Condition{
+		Type:               corev1alpha1.ObjectSetUpgradeable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Available",
+		Message:            "All objects are available.",
+		ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+	}
+	if !meta.IsStatusConditionTrue(*objectSet.GetConditions(), corev1alpha1.ObjectSetAvailable) {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "RolloutInProgress"
+		cond.Message = "Objects are not yet available."
+	}
+	meta.SetStatusCondition(objectSet.GetConditions(), cond)
+}
+
+const teardownBlockedRequeueDelay = time.Minute
+
 func (c *GenericObjectSetController) handleDeletionAndArchival(
 	ctx context.Context, objectSet genericObjectSet,
-) error {
-	// always make sure to remove Available condition
+) (ctrl.Result, error) {
+	// always make sure to remove Available and Upgradeable conditions
 	defer meta.RemoveStatusCondition(objectSet.GetConditions(), corev1alpha1.ObjectSetAvailable)
+	defer meta.RemoveStatusCondition(objectSet.GetConditions(), corev1alpha1.ObjectSetUpgradeable)
 
-	done, err := c.teardownHandler.Teardown(ctx, objectSet)
+	done, requeueAfter, err := c.teardownHandler.Teardown(ctx, objectSet)
+	var crdErr controllers.CRDInstancesExistError
+	if errors.As(err, &crdErr) {
+		// Instances are not watched, so check again later.
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetTeardownBlocked,
+			Status:             metav1.ConditionTrue,
+			Reason:             "CustomResourcesExist",
+			Message:            crdErr.Error(),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return ctrl.Result{RequeueAfter: teardownBlockedRequeueDelay}, nil
+	}
 	if err != nil {
-		return fmt.Errorf("error tearing down during deletion: %w", err)
+		return ctrl.Result{}, fmt.Errorf("error tearing down during deletion: %w", err)
 	}
+	meta.RemoveStatusCondition(objectSet.GetConditions(), corev1alpha1.ObjectSetTeardownBlocked)
 
 	if !done {
 		if objectSet.IsArchived() {
+			message := "Object teardown in progress."
+			if teardown := objectSet.GetTeardownStatus(); teardown != nil {
+				message = fmt.Sprintf("Object teardown in progress, waiting for phase %s.", teardown.Phase)
+			}
 			meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
 				Type:               corev1alpha1.ObjectSetArchived,
 				Status:             metav1.ConditionFalse,
 				Reason:             "ArchivalInProgress",
-				Message:            "Object teardown in progress.",
+				Message:            message,
 				ObservedGeneration: objectSet.ClientObject().GetGeneration(),
 			})
 		}
 		// don't remove finalizer before deletion is done
-		return nil
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
+	orphaned := objectSet.GetOrphanedObjects()
 	if err := controllers.FreeCacheAndRemoveFinalizer(
 		ctx, c.client, objectSet.ClientObject(), c.dynamicCache); err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	// Needs to be called _after_ FreeCacheAndFinalizer,
 	// because .Update is loading new state into objectSet, overriding changes to conditions.
+	objectSet.SetTeardownStatus(nil)
+	objectSet.SetOrphanedObjects(orphaned)
+	objectSet.SetStuckObjects(nil)
+	objectSet.SetManagedObjects(nil)
+	meta.RemoveStatusCondition(objectSet.GetConditions(), corev1alpha1.ObjectSetTeardownBlocked)
 	if objectSet.IsArchived() {
 		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
 			Type:               corev1alpha1.ObjectSetArchived,
@@ -232,5 +456,5 @@ func (c *GenericObjectSetController) handleDeletionAndArchival(
 		})
 	}
 
-	return nil
+	return ctrl.Result{}, nil
 }
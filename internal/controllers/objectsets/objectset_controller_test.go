@@ -0,0 +1,322 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+var testStuckObject = corev1alpha1.ObjectSetStuckObject{
+	ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+		Kind: "ConfigMap", Name: "test", Namespace: "test",
+	},
+	Finalizers: []string{"example.com/cleanup"},
+}
+
+func TestGenericObjectSetController_Reconcile_archivalClearsStatus(t *testing.T) {
+	c := testutil.NewClient()
+	dc := &dynamicCacheMock{}
+	th := &teardownHandlerMock{}
+	mr := &metricsRecorderMock{}
+	controller := &GenericObjectSetController{
+		newObjectSet:    newGenericObjectSet,
+		client:          c,
+		log:             logr.Discard(),
+		scheme:          testScheme,
+		recorder:        record.NewFakeRecorder(10),
+		metricsRecorder: mr,
+		dynamicCache:    dc,
+		teardownHandler: th,
+		statusBatcher:   controllers.NewStatusUpdateBatcher(controllers.DefaultStatusFlushInterval),
+	}
+
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSet")).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1alpha1.ObjectSet)
+			*obj = corev1alpha1.ObjectSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test", Namespace: "test", Generation: 2,
+					Finalizers: []string{controllers.CachedFinalizer},
+				},
+				Spec: corev1alpha1.ObjectSetSpec{
+					LifecycleState: corev1alpha1.ObjectSetLifecycleStateArchived,
+				},
+				Status: corev1alpha1.ObjectSetStatus{
+					Teardown:     &corev1alpha1.ObjectSetTeardownStatus{Phase: "phase-1"},
+					StuckObjects: []corev1alpha1.ObjectSetStuckObject{testStuckObject},
+					ManagedObjects: []corev1alpha1.ObjectSetManagedObject{
+						{ObjectSetObjectReference: testStuckObject.ObjectSetObjectReference, Version: "v1"},
+					},
+				},
+			}
+		}).
+		Return(nil)
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var statusPatch []byte
+	c.StatusMock.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var err error
+			statusPatch, err = args.Get(2).(client.Patch).Data(args.Get(1).(client.Object))
+			require.NoError(t, err)
+		}).
+		Return(nil)
+	dc.On("Free", mock.Anything, mock.Anything).Return(nil)
+	th.On("Teardown", mock.Anything, mock.Anything).Return(true, time.Duration(0), nil)
+	mr.On("RecordObjectSetUnavailableSince", mock.Anything, mock.Anything)
+	mr.On("RecordObjectSetPreviousRevisionMissing", mock.Anything, mock.Anything)
+
+	res, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.True(t, res.IsZero())
+
+	// Fields cleared after teardown have to be nulled explicitly,
+	// otherwise the merge patch would leave them on the server.
+	assert.Contains(t, string(statusPatch), `"teardown":null`)
+	assert.Contains(t, string(statusPatch), `"stuckObjects":null`)
+	assert.Contains(t, string(statusPatch), `"managedObjects":null`)
+	assert.Contains(t, string(statusPatch), `"type":"Archived"`)
+}
+
+func TestGenericObjectSetController_Reconcile_clearsReleasedStuckObjects(t *testing.T) {
+	c := testutil.NewClient()
+	th := &teardownHandlerMock{}
+	mr := &metricsRecorderMock{}
+	controller := &GenericObjectSetController{
+		newObjectSet:    newGenericObjectSet,
+		client:          c,
+		log:             logr.Discard(),
+		scheme:          testScheme,
+		recorder:        record.NewFakeRecorder(10),
+		metricsRecorder: mr,
+		teardownHandler: th,
+		statusBatcher:   controllers.NewStatusUpdateBatcher(controllers.DefaultStatusFlushInterval),
+	}
+
+	c.
+		On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSet")).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1alpha1.ObjectSet)
+			*obj = corev1alpha1.ObjectSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test"},
+				Spec: corev1alpha1.ObjectSetSpec{
+					LifecycleState: corev1alpha1.ObjectSetLifecycleStateArchived,
+				},
+				Status: corev1alpha1.ObjectSetStatus{
+					StuckObjects: []corev1alpha1.ObjectSetStuckObject{testStuckObject},
+				},
+			}
+		}).
+		Return(nil)
+
+	var statusPatch []byte
+	c.StatusMock.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var err error
+			statusPatch, err = args.Get(2).(client.Patch).Data(args.Get(1).(client.Object))
+			require.NoError(t, err)
+		}).
+		Return(nil)
+	// Finalizers have been removed, but teardown continues with the next phase.
+	th.
+		On("Teardown", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(genericObjectSet).SetStuckObjects(nil)
+		}).
+		Return(false, time.Second, nil)
+	mr.On("RecordObjectSetUnavailableSince", mock.Anything, mock.Anything)
+	mr.On("RecordObjectSetPreviousRevisionMissing", mock.Anything, mock.Anything)
+
+	res, err := controller.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test", Namespace: "test"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, res.RequeueAfter)
+	assert.Contains(t, string(statusPatch), `"stuckObjects":null`)
+}
+
+func TestGenericObjectSetController_runReconcilers(t *testing.T) {
+	t.Run("merges results", func(t *testing.T) {
+		precondition, phases, statusCollection := &reconcilerMock{}, &reconcilerMock{}, &reconcilerMock{}
+		controller := &GenericObjectSetController{
+			preconditions: []reconciler{precondition},
+			reconciler:    []reconciler{phases, statusCollection},
+		}
+		precondition.On("Reconcile", mock.Anything, mock.Anything).Return(ctrl.Result{}, nil)
+		// Still rolling out.
+		phases.On("Reconcile", mock.Anything, mock.Anything).
+			Return(ctrl.Result{RequeueAfter: 10 * time.Second}, nil)
+		statusCollection.On("Reconcile", mock.Anything, mock.Anything).
+			Return(ctrl.Result{RequeueAfter: 5 * time.Second}, nil)
+
+		res, err := controller.runReconcilers(context.Background(), &GenericObjectSet{})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 5 * time.Second}, res)
+		statusCollection.AssertCalled(t, "Reconcile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("precondition requeue stops", func(t *testing.T) {
+		precondition, phases := &reconcilerMock{}, &reconcilerMock{}
+		controller := &GenericObjectSetController{
+			preconditions: []reconciler{precondition},
+			reconciler:    []reconciler{phases},
+		}
+		precondition.On("Reconcile", mock.Anything, mock.Anything).
+			Return(ctrl.Result{RequeueAfter: time.Minute}, nil)
+
+		res, err := controller.runReconcilers(context.Background(), &GenericObjectSet{})
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, res)
+		phases.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenericObjectSetController_recordTransitionEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		oldConditions []metav1.Condition
+		newStatus     metav1.ConditionStatus
+		event         string
+	}{
+		{
+			name:      "first reconcile, not available yet",
+			newStatus: metav1.ConditionFalse,
+		},
+		{
+			name:      "first reconcile, available",
+			newStatus: metav1.ConditionTrue,
+			event:     "Normal Available ",
+		},
+		{
+			name: "no longer available",
+			oldConditions: []metav1.Condition{
+				{Type: corev1alpha1.ObjectSetAvailable, Status: metav1.ConditionTrue},
+			},
+			newStatus: metav1.ConditionFalse,
+			event:     "Warning NotAvailable ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			controller := &GenericObjectSetController{recorder: recorder}
+
+			objectSet := &GenericObjectSet{}
+			objectSet.Status.Conditions = []metav1.Condition{
+				{Type: corev1alpha1.ObjectSetAvailable, Status: test.newStatus},
+			}
+			controller.recordTransitionEvents(objectSet, test.oldConditions)
+
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			if len(test.event) == 0 {
+				assert.Empty(t, events)
+			} else {
+				assert.Equal(t, []string{test.event}, events)
+			}
+		})
+	}
+}
+
+type dynamicCacheMock struct {
+	testutil.CtrlClient
+}
+
+func (c *dynamicCacheMock) Source() source.Source {
+	args := c.Called()
+	return args.Get(0).(source.Source)
+}
+
+func (c *dynamicCacheMock) Free(ctx context.Context, obj client.Object) error {
+	args := c.Called(ctx, obj)
+	return args.Error(0)
+}
+
+func (c *dynamicCacheMock) Watch(
+	ctx context.Context, owner client.Object, obj runtime.Object,
+) error {
+	args := c.Called(ctx, owner, obj)
+	return args.Error(0)
+}
+
+type teardownHandlerMock struct {
+	mock.Mock
+}
+
+func (m *teardownHandlerMock) Teardown(
+	ctx context.Context, objectSet genericObjectSet,
+) (cleanupDone bool, requeueAfter time.Duration, err error) {
+	args := m.Called(ctx, objectSet)
+	return args.Bool(0), args.Get(1).(time.Duration), args.Error(2)
+}
+
+type reconcilerMock struct {
+	mock.Mock
+}
+
+func (m *reconcilerMock) Reconcile(
+	ctx context.Context, objectSet genericObjectSet,
+) (ctrl.Result, error) {
+	args := m.Called(ctx, objectSet)
+	return args.Get(0).(ctrl.Result), args.Error(1)
+}
+
+type metricsRecorderMock struct {
+	mock.Mock
+}
+
+func (m *metricsRecorderMock) RecordObjectDriftDetected(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	m.Called(owner, gvk)
+}
+
+func (m *metricsRecorderMock) RecordObjectDriftReverted(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	m.Called(owner, gvk)
+}
+
+func (m *metricsRecorderMock) RecordObjectConflict(
+	owner client.Object, gvk schema.GroupVersionKind, manager string,
+) {
+	m.Called(owner, gvk, manager)
+}
+
+func (m *metricsRecorderMock) RecordObjectSetUnavailableSince(
+	objectSet client.Object, since *time.Time,
+) {
+	m.Called(objectSet, since)
+}
+
+func (m *metricsRecorderMock) RecordObjectSetPreviousRevisionMissing(
+	objectSet client.Object, missing bool,
+) {
+	m.Called(objectSet, missing)
+}
@@ -0,0 +1,186 @@
+package objectsets
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+)
+
+// objectSetRemotePhaseReconciler delegates phases with a .class set
+// to ObjectSetPhase objects that are handled by an auxiliary controller.
+type objectSetRemotePhaseReconciler struct {
+	client            client.Client
+	scheme            *runtime.Scheme
+	newObjectSetPhase genericObjectSetPhaseFactory
+}
+
+func newObjectSetRemotePhaseReconciler(
+	client client.Client,
+	scheme *runtime.Scheme,
+	newObjectSetPhase genericObjectSetPhaseFactory,
+) *objectSetRemotePhaseReconciler {
+	return &objectSetRemotePhaseReconciler{
+		client:            client,
+		scheme:            scheme,
+		newObjectSetPhase: newObjectSetPhase,
+	}
+}
+
+// This error is returned when an ObjectSetPhase with the expected name
+// already exists, but is not controlled by the reconciled ObjectSet.
+type ObjectSetPhaseNotOwnedError struct {
+	ObjectSetPhaseKey client.ObjectKey
+}
+
+func (e ObjectSetPhaseNotOwnedError) Error() string {
+	return fmt.Sprintf("ObjectSetPhase %s already exists and is not owned by this ObjectSet", e.ObjectSetPhaseKey)
+}
+
+// Ensures an ObjectSetPhase object exists for the given phase
+// and reports the phases availability as failed probes.
+// The RemoteClusterReachable condition of the ObjectSetPhase is returned, if reported.
+func (r *objectSetRemotePhaseReconciler) Reconcile(
+	ctx context.Context, objectSet genericObjectSet,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (failedProbes []string, remoteClusterReachable *metav1.Condition, err error) {
+	desired, err := r.desiredObjectSetPhase(objectSet, phase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing := r.newObjectSetPhase(r.scheme)
+	err = r.client.Get(
+		ctx, client.ObjectKeyFromObject(desired.ClientObject()), existing.ClientObject())
+	if errors.IsNotFound(err) {
+		if err := r.client.Create(ctx, desired.ClientObject()); err != nil {
+			return nil, nil, fmt.Errorf("creating ObjectSetPhase: %w", err)
+		}
+		existing = desired
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("getting ObjectSetPhase: %w", err)
+	}
+
+	if !metav1.IsControlledBy(existing.ClientObject(), objectSet.ClientObject()) {
+		return nil, nil, ObjectSetPhaseNotOwnedError{
+			ObjectSetPhaseKey: client.ObjectKeyFromObject(existing.ClientObject()),
+		}
+	}
+
+	// The lifecycle state is the only mutable field of an ObjectSetPhase.
+	if existing.GetLifecycleState() != desired.GetLifecycleState() {
+		existing.SetLifecycleState(desired.GetLifecycleState())
+		if err := r.client.Update(ctx, existing.ClientObject()); err != nil {
+			return nil, nil, fmt.Errorf("updating ObjectSetPhase lifecycle state: %w", err)
+		}
+	}
+
+	remoteClusterReachable = meta.FindStatusCondition(
+		existing.GetConditions(), corev1alpha1.ObjectSetRemoteClusterReachable)
+	return objectSetPhaseFailedProbes(existing), remoteClusterReachable, nil
+}
+
+// Deletes the ObjectSetPhase object of the given phase.
+// Cleanup is only done after the ObjectSetPhase is gone,
+// because the auxiliary controller has to tear down all objects first.
+func (r *objectSetRemotePhaseReconciler) Teardown(
+	ctx context.Context, objectSet genericObjectSet,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (cleanupDone bool, err error) {
+	objectSetPhase := r.newObjectSetPhase(r.scheme)
+	key := client.ObjectKey{
+		Name:      objectSetPhaseName(objectSet, phase),
+		Namespace: objectSet.ClientObject().GetNamespace(),
+	}
+	err = r.client.Get(ctx, key, objectSetPhase.ClientObject())
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting ObjectSetPhase for teardown: %w", err)
+	}
+
+	if !metav1.IsControlledBy(objectSetPhase.ClientObject(), objectSet.ClientObject()) {
+		// Not ours to delete.
+		return true, nil
+	}
+
+	if !objectSetPhase.ClientObject().GetDeletionTimestamp().IsZero() {
+		// Waiting for the auxiliary controller to finish teardown.
+		return false, nil
+	}
+
+	if err := r.client.Delete(ctx, objectSetPhase.ClientObject()); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, fmt.Errorf("deleting ObjectSetPhase for teardown: %w", err)
+	}
+	return false, nil
+}
+
+func (r *objectSetRemotePhaseReconciler) desiredObjectSetPhase(
+	objectSet genericObjectSet,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (genericObjectSetPhase, error) {
+	objectSetPhase := r.newObjectSetPhase(r.scheme)
+	obj := objectSetPhase.ClientObject()
+	obj.SetName(objectSetPhaseName(objectSet, phase))
+	obj.SetNamespace(objectSet.ClientObject().GetNamespace())
+	if shard, ok := objectSet.ClientObject().GetLabels()[controllers.ShardLabel]; ok {
+		// Keep phases on the same shard as their ObjectSet.
+		obj.SetLabels(map[string]string{controllers.ShardLabel: shard})
+	}
+
+	objectSetPhase.SetPhase(phase)
+	objectSetPhase.SetRevision(objectSet.GetStatusRevision())
+	objectSetPhase.SetPrevious(objectSet.GetPrevious())
+	objectSetPhase.SetAvailabilityProbes(objectSet.GetAvailabilityProbes())
+	if objectSet.IsPaused() {
+		objectSetPhase.SetLifecycleState(corev1alpha1.ObjectSetLifecycleStatePaused)
+	} else {
+		objectSetPhase.SetLifecycleState(corev1alpha1.ObjectSetLifecycleStateActive)
+	}
+
+	if err := controllerutil.SetControllerReference(
+		objectSet.ClientObject(), obj, r.scheme); err != nil {
+		return nil, fmt.Errorf("setting controller reference on ObjectSetPhase: %w", err)
+	}
+	return objectSetPhase, nil
+}
+
+// Translates the Available condition of an ObjectSetPhase into failed probe messages.
+func objectSetPhaseFailedProbes(objectSetPhase genericObjectSetPhase) []string {
+	obj := objectSetPhase.ClientObject()
+	if meta.IsStatusConditionFalse(
+		objectSetPhase.GetConditions(), corev1alpha1.ObjectSetRemoteClusterReachable) {
+		// Availability can't be checked, while the remote cluster is unreachable.
+		return []string{fmt.Sprintf("ObjectSetPhase %s: remote cluster unreachable", obj.GetName())}
+	}
+	availableCond := meta.FindStatusCondition(
+		objectSetPhase.GetConditions(), corev1alpha1.ObjectSetAvailable)
+	switch {
+	case availableCond == nil:
+		return []string{fmt.Sprintf("ObjectSetPhase %s: Available condition not reported", obj.GetName())}
+	case availableCond.ObservedGeneration != obj.GetGeneration():
+		return []string{fmt.Sprintf("ObjectSetPhase %s: Available condition outdated", obj.GetName())}
+	case availableCond.Status != metav1.ConditionTrue:
+		return []string{fmt.Sprintf("ObjectSetPhase %s: %s", obj.GetName(), availableCond.Message)}
+	}
+	return nil
+}
+
+// ObjectSetPhases are named after the ObjectSet and the phase they belong to.
+func objectSetPhaseName(
+	objectSet genericObjectSet, phase corev1alpha1.ObjectSetTemplatePhase,
+) string {
+	return objectSet.ClientObject().GetName() + "-" + phase.Name
+}
@@ -0,0 +1,240 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func Test_objectSetRemotePhaseReconciler(t *testing.T) {
+	objectSet := &GenericObjectSet{
+		corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-os",
+				Namespace: "xxx",
+				UID:       "123",
+			},
+			Spec: corev1alpha1.ObjectSetSpec{
+				LifecycleState: corev1alpha1.ObjectSetLifecycleStatePaused,
+			},
+		},
+	}
+	phase := corev1alpha1.ObjectSetTemplatePhase{
+		Name:  "phase-1",
+		Class: "hosted-cluster",
+	}
+
+	t.Run("creates ObjectSetPhase", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, client.ObjectKey{
+				Name:      "my-os-phase-1",
+				Namespace: "xxx",
+			}, mock.Anything).
+			Return(errors.NewNotFound(schema.GroupResource{}, ""))
+		testClient.
+			On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		failedProbes, _, err := r.Reconcile(ctx, objectSet, phase)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"ObjectSetPhase my-os-phase-1: Available condition not reported",
+		}, failedProbes)
+
+		testClient.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		created := testClient.Calls[1].Arguments.Get(1).(*corev1alpha1.ObjectSetPhase)
+		assert.Equal(t, phase, created.Spec.ObjectSetTemplatePhase)
+		assert.Equal(t,
+			corev1alpha1.ObjectSetLifecycleStatePaused, created.Spec.LifecycleState)
+		assert.True(t, metav1.IsControlledBy(created, &objectSet.ObjectSet))
+	})
+
+	t.Run("reports availability", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				out := args.Get(2).(*corev1alpha1.ObjectSetPhase)
+				out.Name = "my-os-phase-1"
+				out.Generation = 2
+				out.OwnerReferences = []metav1.OwnerReference{
+					{UID: "123", Controller: boolPtr(true)},
+				}
+				out.Spec.LifecycleState = corev1alpha1.ObjectSetLifecycleStatePaused
+				out.Status.Conditions = []metav1.Condition{
+					{
+						Type:               corev1alpha1.ObjectSetAvailable,
+						Status:             metav1.ConditionFalse,
+						ObservedGeneration: 2,
+						Message:            "xxx",
+					},
+				}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		failedProbes, _, err := r.Reconcile(ctx, objectSet, phase)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"ObjectSetPhase my-os-phase-1: xxx"}, failedProbes)
+		testClient.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports remote cluster unreachable", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				out := args.Get(2).(*corev1alpha1.ObjectSetPhase)
+				out.Name = "my-os-phase-1"
+				out.OwnerReferences = []metav1.OwnerReference{
+					{UID: "123", Controller: boolPtr(true)},
+				}
+				out.Spec.LifecycleState = corev1alpha1.ObjectSetLifecycleStatePaused
+				out.Status.Conditions = []metav1.Condition{
+					{
+						Type:   corev1alpha1.ObjectSetAvailable,
+						Status: metav1.ConditionTrue,
+					},
+					{
+						Type:    corev1alpha1.ObjectSetRemoteClusterReachable,
+						Status:  metav1.ConditionFalse,
+						Message: "connection refused",
+					},
+				}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		failedProbes, remoteClusterReachable, err := r.Reconcile(ctx, objectSet, phase)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"ObjectSetPhase my-os-phase-1: remote cluster unreachable",
+		}, failedProbes)
+		if assert.NotNil(t, remoteClusterReachable) {
+			assert.Equal(t, "connection refused", remoteClusterReachable.Message)
+		}
+	})
+
+	t.Run("errors on ObjectSetPhase not owned", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		_, _, err := r.Reconcile(ctx, objectSet, phase)
+		assert.ErrorAs(t, err, &ObjectSetPhaseNotOwnedError{})
+	})
+
+	t.Run("teardown deletes ObjectSetPhase", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				out := args.Get(2).(*corev1alpha1.ObjectSetPhase)
+				out.OwnerReferences = []metav1.OwnerReference{
+					{UID: "123", Controller: boolPtr(true)},
+				}
+			}).
+			Return(nil)
+		testClient.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		done, err := r.Teardown(ctx, objectSet, phase)
+		require.NoError(t, err)
+
+		assert.False(t, done)
+		testClient.AssertCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("teardown done when ObjectSetPhase is gone", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := newObjectSetRemotePhaseReconciler(
+			testClient, testScheme, newGenericObjectSetPhase)
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.NewNotFound(schema.GroupResource{}, ""))
+
+		ctx := context.Background()
+		done, err := r.Teardown(ctx, objectSet, phase)
+		require.NoError(t, err)
+
+		assert.True(t, done)
+	})
+}
+
+func Test_reportRemoteClusterReachable(t *testing.T) {
+	t.Run("removes condition without remote phases", func(t *testing.T) {
+		objectSet := &GenericObjectSet{}
+		objectSet.Status.Conditions = []metav1.Condition{
+			{Type: corev1alpha1.ObjectSetRemoteClusterReachable},
+		}
+
+		reportRemoteClusterReachable(objectSet, map[string]*metav1.Condition{})
+		assert.Empty(t, objectSet.Status.Conditions)
+	})
+
+	t.Run("aggregates unreachable phases", func(t *testing.T) {
+		objectSet := &GenericObjectSet{}
+
+		reportRemoteClusterReachable(objectSet, map[string]*metav1.Condition{
+			"b": {Status: metav1.ConditionFalse, Message: "timeout"},
+			"a": {Status: metav1.ConditionTrue},
+			"c": {Status: metav1.ConditionFalse, Message: "connection refused"},
+		})
+		cond := meta.FindStatusCondition(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetRemoteClusterReachable)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, `Phase "b": timeout, Phase "c": connection refused`, cond.Message)
+		}
+	})
+
+	t.Run("all reachable", func(t *testing.T) {
+		objectSet := &GenericObjectSet{}
+
+		reportRemoteClusterReachable(objectSet, map[string]*metav1.Condition{
+			"a": {Status: metav1.ConditionTrue},
+		})
+		assert.True(t, meta.IsStatusConditionTrue(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetRemoteClusterReachable))
+	})
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
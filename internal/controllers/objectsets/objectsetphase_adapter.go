@@ -11,6 +11,12 @@ import (
 type genericObjectSetPhase interface {
 	ClientObject() client.Object
 	GetConditions() []metav1.Condition
+	GetLifecycleState() corev1alpha1.ObjectSetLifecycleState
+	SetLifecycleState(state corev1alpha1.ObjectSetLifecycleState)
+	SetRevision(revision int64)
+	SetPrevious(previous []corev1alpha1.PreviousRevisionReference)
+	SetAvailabilityProbes(probes []corev1alpha1.ObjectSetProbe)
+	SetPhase(phase corev1alpha1.ObjectSetTemplatePhase)
 }
 
 type genericObjectSetPhaseFactory func(
@@ -58,6 +64,30 @@ func (a *GenericObjectSetPhase) GetConditions() []metav1.Condition {
 	return a.Status.Conditions
 }
 
+func (a *GenericObjectSetPhase) GetLifecycleState() corev1alpha1.ObjectSetLifecycleState {
+	return a.Spec.LifecycleState
+}
+
+func (a *GenericObjectSetPhase) SetLifecycleState(state corev1alpha1.ObjectSetLifecycleState) {
+	a.Spec.LifecycleState = state
+}
+
+func (a *GenericObjectSetPhase) SetRevision(revision int64) {
+	a.Spec.Revision = revision
+}
+
+func (a *GenericObjectSetPhase) SetPrevious(previous []corev1alpha1.PreviousRevisionReference) {
+	a.Spec.Previous = previous
+}
+
+func (a *GenericObjectSetPhase) SetAvailabilityProbes(probes []corev1alpha1.ObjectSetProbe) {
+	a.Spec.AvailabilityProbes = probes
+}
+
+func (a *GenericObjectSetPhase) SetPhase(phase corev1alpha1.ObjectSetTemplatePhase) {
+	a.Spec.ObjectSetTemplatePhase = phase
+}
+
 type GenericClusterObjectSetPhase struct {
 	corev1alpha1.ClusterObjectSetPhase
 }
@@ -69,3 +99,27 @@ func (a *GenericClusterObjectSetPhase) ClientObject() client.Object {
 func (a *GenericClusterObjectSetPhase) GetConditions() []metav1.Condition {
 	return a.Status.Conditions
 }
+
+func (a *GenericClusterObjectSetPhase) GetLifecycleState() corev1alpha1.ObjectSetLifecycleState {
+	return a.Spec.LifecycleState
+}
+
+func (a *GenericClusterObjectSetPhase) SetLifecycleState(state corev1alpha1.ObjectSetLifecycleState) {
+	a.Spec.LifecycleState = state
+}
+
+func (a *GenericClusterObjectSetPhase) SetRevision(revision int64) {
+	a.Spec.Revision = revision
+}
+
+func (a *GenericClusterObjectSetPhase) SetPrevious(previous []corev1alpha1.PreviousRevisionReference) {
+	a.Spec.Previous = previous
+}
+
+func (a *GenericClusterObjectSetPhase) SetAvailabilityProbes(probes []corev1alpha1.ObjectSetProbe) {
+	a.Spec.AvailabilityProbes = probes
+}
+
+func (a *GenericClusterObjectSetPhase) SetPhase(phase corev1alpha1.ObjectSetTemplatePhase) {
+	a.Spec.ObjectSetTemplatePhase = phase
+}
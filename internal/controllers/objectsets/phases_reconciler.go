@@ -2,10 +2,15 @@ package objectsets
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -19,23 +24,31 @@ import (
 
 // phasesReconciler reconciles all phases within an ObjectSet.
 type phasesReconciler struct {
-	client          client.Client
-	phaseReconciler phaseReconciler
-	scheme          *runtime.Scheme
-	newObjectSet    genericObjectSetFactory
+	client                client.Client
+	phaseReconciler       phaseReconciler
+	remotePhaseReconciler remotePhaseReconciler
+	scheme                *runtime.Scheme
+	newObjectSet          genericObjectSetFactory
+	newObjectSetPhase     genericObjectSetPhaseFactory
+	now                   func() time.Time
 }
 
 func newPhasesReconciler(
 	client client.Client,
 	phaseReconciler phaseReconciler,
+	remotePhaseReconciler remotePhaseReconciler,
 	scheme *runtime.Scheme,
 	newObjectSet genericObjectSetFactory,
+	newObjectSetPhase genericObjectSetPhaseFactory,
 ) *phasesReconciler {
 	return &phasesReconciler{
-		client:          client,
-		phaseReconciler: phaseReconciler,
-		scheme:          scheme,
-		newObjectSet:    newObjectSet,
+		client:                client,
+		phaseReconciler:       phaseReconciler,
+		remotePhaseReconciler: remotePhaseReconciler,
+		scheme:                scheme,
+		newObjectSet:          newObjectSet,
+		newObjectSetPhase:     newObjectSetPhase,
+		now:                   time.Now,
 	}
 }
 
@@ -46,12 +59,29 @@ type phaseReconciler interface {
 		probe probing.Prober, previous []client.Object,
 	) (failedProbes []string, err error)
 
+	DetectPhaseDrift(
+		ctx context.Context, owner controllers.PhaseObjectOwner,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+	) (drifted []string, err error)
+
 	TeardownPhase(
 		ctx context.Context, owner controllers.PhaseObjectOwner,
 		phase corev1alpha1.ObjectSetTemplatePhase,
 	) (cleanupDone bool, err error)
 }
 
+type remotePhaseReconciler interface {
+	Reconcile(
+		ctx context.Context, objectSet genericObjectSet,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+	) (failedProbes []string, remoteClusterReachable *metav1.Condition, err error)
+
+	Teardown(
+		ctx context.Context, objectSet genericObjectSet,
+		phase corev1alpha1.ObjectSetTemplatePhase,
+	) (cleanupDone bool, err error)
+}
+
 func (r *phasesReconciler) Reconcile(
 	ctx context.Context, objectSet genericObjectSet,
 ) (res ctrl.Result, err error) {
@@ -65,31 +95,58 @@ func (r *phasesReconciler) Reconcile(
 	if err != nil {
 		return res, fmt.Errorf("parsing probes: %w", err)
 	}
-	for _, phase := range objectSet.GetPhases() {
-		var (
-			failedProbes []string
-			err          error
-		)
-		if len(phase.Class) > 0 {
-			failedProbes, err = r.reconcileRemotePhase(
-				ctx, objectSet, phase)
-		} else {
-			failedProbes, err = r.reconcileLocalPhase(
-				ctx, objectSet, phase, probe, previous)
+	// Conflicts are recorded again by phases still failing to adopt objects.
+	objectSet.SetConflicts(nil)
+	remoteClusterReachability := map[string]*metav1.Condition{}
+	var drifted []string
+	defer func() {
+		if err == nil {
+			reportRemoteClusterReachable(objectSet, remoteClusterReachability)
+			controllers.ReportDriftDetected(
+				objectSet.GetConditions(), objectSet.ClientObject().GetGeneration(),
+				objectSet.IsPaused(), drifted)
 		}
-		if err != nil {
-			return ctrl.Result{}, err
+	}()
+	for _, group := range parallelGroups(objectSet.GetPhases()) {
+		results := r.reconcilePhaseGroup(ctx, objectSet, group, probe, previous)
+		recordPhaseResults(objectSet, results)
+
+		var failedPhases []string
+		for i, phase := range group {
+			result := results[i]
+			var settlingErr controllers.ObjectSettlingError
+			if errors.As(result.err, &settlingErr) {
+				// Reported like a failing probe, until the object has settled.
+				result.failedProbes = append(result.failedProbes, settlingErr.Error())
+				res = controllers.MergeResults(res, ctrl.Result{RequeueAfter: settlingErr.RequeueAfter})
+			} else if result.err != nil {
+				return ctrl.Result{}, result.err
+			}
+			if result.remoteClusterReachable != nil {
+				remoteClusterReachability[phase.Name] = result.remoteClusterReachable
+			}
+			drifted = append(drifted, result.drifted...)
+
+			if len(result.failedProbes) > 0 {
+				failedPhases = append(failedPhases, fmt.Sprintf(
+					"Phase %q failed: %s", phase.Name, strings.Join(result.failedProbes, ", ")))
+				if len(phase.ExternalObjects) > 0 && len(phase.Class) == 0 {
+					res = controllers.MergeResults(res, ctrl.Result{
+						RequeueAfter: controllers.ExternalObjectsRecheckInterval,
+					})
+				}
+			}
 		}
 
-		if len(failedProbes) > 0 {
+		if len(failedPhases) > 0 {
 			meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
 				Type:               corev1alpha1.ObjectSetAvailable,
 				Status:             metav1.ConditionFalse,
 				Reason:             "ProbeFailure",
-				Message:            fmt.Sprintf("Phase %q failed: %s", phase.Name, strings.Join(failedProbes, ", ")),
+				Message:            strings.Join(failedPhases, "; "),
 				ObservedGeneration: objectSet.ClientObject().GetGeneration(),
 			})
-			return ctrl.Result{}, nil
+			return res, nil
 		}
 	}
 
@@ -116,62 +173,274 @@ func (r *phasesReconciler) Reconcile(
 	return
 }
 
+// Splits phases into groups of consecutive phases sharing the same parallel group.
+// Phases without a parallel group form a group on their own.
+func parallelGroups(
+	phases []corev1alpha1.ObjectSetTemplatePhase,
+) [][]corev1alpha1.ObjectSetTemplatePhase {
+	var groups [][]corev1alpha1.ObjectSetTemplatePhase
+	for i, phase := range phases {
+		if i > 0 && len(phase.ParallelGroup) > 0 &&
+			phase.ParallelGroup == phases[i-1].ParallelGroup {
+			groups[len(groups)-1] = append(groups[len(groups)-1], phase)
+			continue
+		}
+		groups = append(groups, []corev1alpha1.ObjectSetTemplatePhase{phase})
+	}
+	return groups
+}
+
+type phaseResult struct {
+	failedProbes           []string
+	drifted                []string
+	remoteClusterReachable *metav1.Condition
+	conflicts              []corev1alpha1.ObjectSetConflict
+	managedObjects         []corev1alpha1.ObjectSetManagedObject
+	err                    error
+}
+
+// Merges objects recorded by the phases of a group into the ObjectSet status,
+// in the order of the phases, after all of them are done.
+func recordPhaseResults(objectSet genericObjectSet, results []phaseResult) {
+	for _, result := range results {
+		for _, conflict := range result.conflicts {
+			objectSet.RecordConflict(conflict)
+		}
+		for _, managed := range result.managedObjects {
+			objectSet.RecordManagedObject(managed)
+		}
+	}
+}
+
+// Records objects of a single phase instead of writing them into the ObjectSet status,
+// so phases of a parallel group don't modify the same ObjectSet concurrently.
+type phaseObjectRecorder struct {
+	genericObjectSet
+	conflicts      []corev1alpha1.ObjectSetConflict
+	managedObjects []corev1alpha1.ObjectSetManagedObject
+}
+
+func (r *phaseObjectRecorder) RecordConflict(conflict corev1alpha1.ObjectSetConflict) {
+	r.conflicts = append(r.conflicts, conflict)
+}
+
+func (r *phaseObjectRecorder) RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject) {
+	r.managedObjects = append(r.managedObjects, managed)
+}
+
+// Reconciles all phases of a group concurrently.
+// Results are returned in the order of the given phases.
+func (r *phasesReconciler) reconcilePhaseGroup(
+	ctx context.Context, objectSet genericObjectSet,
+	group []corev1alpha1.ObjectSetTemplatePhase,
+	probe probing.Prober, previous []client.Object,
+) []phaseResult {
+	results := make([]phaseResult, len(group))
+	if len(group) == 1 {
+		results[0] = r.reconcilePhase(ctx, objectSet, group[0], probe, previous)
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i := range group {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.reconcilePhase(ctx, objectSet, group[i], probe, previous)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *phasesReconciler) reconcilePhase(
+	ctx context.Context, objectSet genericObjectSet,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+	probe probing.Prober, previous []client.Object,
+) (result phaseResult) {
+	if len(phase.Class) > 0 {
+		result.failedProbes, result.remoteClusterReachable, result.err = r.reconcileRemotePhase(
+			ctx, objectSet, phase)
+		return
+	}
+	// The recorder hides the ServiceAccount of the ObjectSet,
+	// so impersonation is set up here instead of by the phase reconciler.
+	ctx = controllers.WithOwnerServiceAccount(ctx, objectSet)
+	recorder := &phaseObjectRecorder{genericObjectSet: objectSet}
+	result.failedProbes, result.drifted, result.err = r.reconcileLocalPhase(
+		ctx, recorder, phase, probe, previous)
+	result.conflicts = recorder.conflicts
+	result.managedObjects = recorder.managedObjects
+	return
+}
+
 // Reconciles the Phase via an ObjectSetPhase object,
 // delegating the task to an auxiliary controller.
 func (r *phasesReconciler) reconcileRemotePhase(
 	ctx context.Context, objectSet genericObjectSet,
 	phase corev1alpha1.ObjectSetTemplatePhase,
-) (failedProbes []string, err error) {
-	// TODO!
-	return
+) (failedProbes []string, remoteClusterReachable *metav1.Condition, err error) {
+	return r.remotePhaseReconciler.Reconcile(ctx, objectSet, phase)
+}
+
+// Aggregates the RemoteClusterReachable conditions of remote phases by phase name.
+// The condition is removed, when no phase reports it.
+func reportRemoteClusterReachable(
+	objectSet genericObjectSet, reachability map[string]*metav1.Condition,
+) {
+	if len(reachability) == 0 {
+		meta.RemoveStatusCondition(
+			objectSet.GetConditions(), corev1alpha1.ObjectSetRemoteClusterReachable)
+		return
+	}
+
+	var unreachable []string
+	for phaseName, cond := range reachability {
+		if cond.Status != metav1.ConditionTrue {
+			unreachable = append(unreachable, fmt.Sprintf("Phase %q: %s", phaseName, cond.Message))
+		}
+	}
+	if len(unreachable) > 0 {
+		sort.Strings(unreachable)
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetRemoteClusterReachable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "RemoteClusterUnreachable",
+			Message:            strings.Join(unreachable, ", "),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return
+	}
+
+	meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+		Type:               corev1alpha1.ObjectSetRemoteClusterReachable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RemoteClusterReachable",
+		Message:            "All remote clusters are reachable.",
+		ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+	})
 }
 
 // Reconciles the Phase directly in-process.
+// While paused, objects that differ from their desired state are returned as drifted.
 func (r *phasesReconciler) reconcileLocalPhase(
 	ctx context.Context, objectSet genericObjectSet,
 	phase corev1alpha1.ObjectSetTemplatePhase,
 	probe probing.Prober, previous []client.Object,
-) ([]string, error) {
-	return r.phaseReconciler.ReconcilePhase(
+) (failedProbes, drifted []string, err error) {
+	failedProbes, err = r.phaseReconciler.ReconcilePhase(
 		ctx, objectSet, phase, probe, previous)
+	if err != nil || !objectSet.IsPaused() {
+		return failedProbes, nil, err
+	}
+
+	drifted, err = r.phaseReconciler.DetectPhaseDrift(ctx, objectSet, phase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("detecting drift: %w", err)
+	}
+	return failedProbes, drifted, nil
 }
 
+// Previous revisions are referenced by ObjectSet name.
+// Objects of those revisions are either controlled by the ObjectSet itself
+// or by one of the ObjectSetPhases that the ObjectSet delegated a phase to,
+// so objects are handed back when a later revision reconciles the phase in-process.
 func (r *phasesReconciler) lookupPreviousRevisions(
 	ctx context.Context, objectSet genericObjectSet,
 ) ([]client.Object, error) {
-	previous := objectSet.GetPrevious()
-	previousSets := make([]client.Object, len(previous))
-	for i, prev := range objectSet.GetPrevious() {
+	namespace := objectSet.ClientObject().GetNamespace()
+	var previous []client.Object
+	for _, prev := range objectSet.GetPrevious() {
 		set := r.newObjectSet(r.scheme)
 		if err := r.client.Get(
 			ctx, client.ObjectKey{
-				Name: prev.Name, Namespace: objectSet.ClientObject().GetNamespace(),
+				Name: prev.Name, Namespace: namespace,
 			}, set.ClientObject()); err != nil {
 			return nil, err
 		}
-		previousSets[i] = set.ClientObject()
+		previous = append(previous, set.ClientObject())
+
+		for _, phase := range set.GetPhases() {
+			if len(phase.Class) == 0 {
+				continue
+			}
+			objectSetPhase := r.newObjectSetPhase(r.scheme)
+			err := r.client.Get(ctx, client.ObjectKey{
+				Name: objectSetPhaseName(set, phase), Namespace: namespace,
+			}, objectSetPhase.ClientObject())
+			if apierrors.IsNotFound(err) {
+				// Already torn down, nothing to take over.
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			previous = append(previous, objectSetPhase.ClientObject())
+		}
 	}
-	return previousSets, nil
+	return previous, nil
 }
 
+// Tears down phases in reverse order.
+// A phase is only torn down after all objects of the following phases are gone,
+// including objects waiting for their finalizers.
+// Phases blocking teardown for longer than their teardownTimeout are skipped.
+// The phase currently blocking teardown is reported in .status.teardown.
 func (r *phasesReconciler) Teardown(
 	ctx context.Context, objectSet genericObjectSet,
-) (cleanupDone bool, err error) {
+) (cleanupDone bool, requeueAfter time.Duration, err error) {
 	log := logr.FromContextOrDiscard(ctx)
+	// reported again by phases still blocking teardown.
+	objectSet.SetStuckObjects(nil)
 
-	phases := objectSet.GetPhases()
+	// copy to not mutate the spec.
+	phases := append([]corev1alpha1.ObjectSetTemplatePhase{}, objectSet.GetPhases()...)
 	reverse(phases) // teardown in reverse order
 
-	for _, phase := range phases {
-		if cleanupDone, err := r.teardownPhase(ctx, objectSet, phase); err != nil {
-			return false, fmt.Errorf("error archiving phase: %w", err)
-		} else if !cleanupDone {
-			return false, nil
+	// index of the phase reported in status.
+	teardown := objectSet.GetTeardownStatus()
+	current := -1
+	for i := range phases {
+		if teardown != nil && phases[i].Name == teardown.Phase {
+			current = i
+			break
+		}
+	}
+
+	now := r.now()
+	for i, phase := range phases {
+		cleanupDone, err := r.teardownPhase(ctx, objectSet, phase)
+		if err != nil {
+			return false, 0, fmt.Errorf("error archiving phase: %w", err)
+		}
+		if cleanupDone {
+			log.Info("cleanup done", "phase", phase.Name)
+			continue
+		}
+		if i < current {
+			// Teardown of this phase already timed out.
+			continue
+		}
+
+		if i != current {
+			current = i
+			teardown = &corev1alpha1.ObjectSetTeardownStatus{
+				Phase: phase.Name,
+				Since: metav1.NewTime(now),
+			}
+			objectSet.SetTeardownStatus(teardown)
+		}
+		if phase.TeardownTimeout == nil {
+			return false, 0, nil
+		}
+		deadline := teardown.Since.Add(phase.TeardownTimeout.Duration)
+		if now.Before(deadline) {
+			return false, deadline.Sub(now), nil
 		}
-		log.Info("cleanup done", "phase", phase.Name)
+		log.Info("teardown timed out, continuing with next phase", "phase", phase.Name)
 	}
 
-	return true, nil
+	return true, 0, nil
 }
 
 func (r *phasesReconciler) teardownPhase(
@@ -188,8 +457,7 @@ func (r *phasesReconciler) teardownRemotePhase(
 	ctx context.Context, objectSet genericObjectSet,
 	phase corev1alpha1.ObjectSetTemplatePhase,
 ) (cleanupDone bool, err error) {
-	// TODO!
-	return true, nil
+	return r.remotePhaseReconciler.Teardown(ctx, objectSet, phase)
 }
 
 // reverse the order of a slice.
@@ -0,0 +1,353 @@
+package objectsets
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/ownerhandling"
+	"package-operator.run/package-operator/internal/probing"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func Test_phasesReconciler_Teardown(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newObjectSet := func() *GenericObjectSet {
+		return &GenericObjectSet{
+			corev1alpha1.ObjectSet{
+				Spec: corev1alpha1.ObjectSetSpec{
+					ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+						Phases: []corev1alpha1.ObjectSetTemplatePhase{
+							{Name: "phase-1"},
+							{
+								Name:            "phase-2",
+								TeardownTimeout: &metav1.Duration{Duration: time.Minute},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("tears down phases in reverse order", func(t *testing.T) {
+		pr := &phaseReconcilerMock{}
+		r := newPhasesReconciler(testutil.NewClient(), pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+		r.now = func() time.Time { return now }
+
+		objectSet := newObjectSet()
+		pr.On("TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-2")).
+			Return(true, nil)
+		pr.On("TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-1")).
+			Return(false, nil)
+
+		done, requeueAfter, err := r.Teardown(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Zero(t, requeueAfter)
+		assert.Equal(t, &corev1alpha1.ObjectSetTeardownStatus{
+			Phase: "phase-1",
+			Since: metav1.NewTime(now),
+		}, objectSet.Status.Teardown)
+		// spec must not be modified
+		assert.Equal(t, "phase-1", objectSet.Spec.Phases[0].Name)
+	})
+
+	t.Run("waits for teardown timeout", func(t *testing.T) {
+		pr := &phaseReconcilerMock{}
+		r := newPhasesReconciler(testutil.NewClient(), pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+		r.now = func() time.Time { return now }
+
+		objectSet := newObjectSet()
+		objectSet.Status.Teardown = &corev1alpha1.ObjectSetTeardownStatus{
+			Phase: "phase-2",
+			Since: metav1.NewTime(now.Add(-20 * time.Second)),
+		}
+		pr.On("TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-2")).
+			Return(false, nil)
+
+		done, requeueAfter, err := r.Teardown(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, 40*time.Second, requeueAfter)
+		pr.AssertNotCalled(t, "TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-1"))
+	})
+
+	t.Run("continues after teardown timeout", func(t *testing.T) {
+		pr := &phaseReconcilerMock{}
+		r := newPhasesReconciler(testutil.NewClient(), pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+		r.now = func() time.Time { return now }
+
+		objectSet := newObjectSet()
+		objectSet.Status.Teardown = &corev1alpha1.ObjectSetTeardownStatus{
+			Phase: "phase-2",
+			Since: metav1.NewTime(now.Add(-2 * time.Minute)),
+		}
+		pr.On("TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-2")).
+			Return(false, nil)
+		pr.On("TeardownPhase", mock.Anything, objectSet, phaseNamed("phase-1")).
+			Return(true, nil)
+
+		done, _, err := r.Teardown(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.True(t, done)
+	})
+}
+
+func Test_phasesReconciler_Reconcile_parallelGroup(t *testing.T) {
+	pr := &phaseReconcilerMock{}
+	r := newPhasesReconciler(testutil.NewClient(), pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+
+	objectSet := &GenericObjectSet{
+		corev1alpha1.ObjectSet{
+			Spec: corev1alpha1.ObjectSetSpec{
+				ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+					Phases: []corev1alpha1.ObjectSetTemplatePhase{
+						{Name: "phase-1", ParallelGroup: "a"},
+						{Name: "phase-2", ParallelGroup: "a"},
+						{Name: "phase-3"},
+					},
+				},
+			},
+		},
+	}
+	pr.On("ReconcilePhase", mock.Anything, mock.Anything, phaseNamed("phase-1"), mock.Anything, mock.Anything).
+		Return([]string{"not ready"}, nil)
+	pr.On("ReconcilePhase", mock.Anything, mock.Anything, phaseNamed("phase-2"), mock.Anything, mock.Anything).
+		Return([]string{}, nil)
+
+	res, err := r.Reconcile(context.Background(), objectSet)
+	require.NoError(t, err)
+	assert.True(t, res.IsZero())
+
+	// phase-2 is reconciled although phase-1 failed, phase-3 waits for both.
+	pr.AssertNumberOfCalls(t, "ReconcilePhase", 2)
+	cond := meta.FindStatusCondition(objectSet.Status.Conditions, corev1alpha1.ObjectSetAvailable)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, `Phase "phase-1" failed: not ready`, cond.Message)
+	}
+}
+
+func Test_phasesReconciler_Reconcile_pausedMissingObjectDrift(t *testing.T) {
+	c := testutil.NewClient()
+	dynamicCache := &dynamicCacheMock{}
+	dynamicCache.
+		On("Watch", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	// The object was deleted while the ObjectSet is paused.
+	dynamicCache.
+		On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Return(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cm"))
+	pr := controllers.NewPhaseReconciler(
+		testScheme, c, c, dynamicCache, ownerhandling.NewNative(testScheme),
+		nil, controllers.KindPolicy{}, false, nil)
+	r := newPhasesReconciler(c, pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+
+	objectSet := &GenericObjectSet{
+		corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "test", UID: "123"},
+			Spec: corev1alpha1.ObjectSetSpec{
+				LifecycleState: corev1alpha1.ObjectSetLifecycleStatePaused,
+				ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+					Phases: []corev1alpha1.ObjectSetTemplatePhase{
+						{
+							Name: "phase-1",
+							Objects: []corev1alpha1.ObjectSetObject{
+								{Object: runtime.RawExtension{
+									Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`),
+								}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := r.Reconcile(context.Background(), objectSet)
+	require.NoError(t, err)
+
+	cond := meta.FindStatusCondition(objectSet.Status.Conditions, corev1alpha1.ObjectSetDriftDetected)
+	if assert.NotNil(t, cond) {
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Contains(t, cond.Message, "ConfigMap test/cm (missing)")
+	}
+	// Nothing is created while paused.
+	c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Run with -race: phases of a parallel group must not write into the same ObjectSet concurrently.
+func Test_phasesReconciler_Reconcile_parallelGroupRecordsManagedObjects(t *testing.T) {
+	pr := &phaseReconcilerMock{}
+	r := newPhasesReconciler(testutil.NewClient(), pr, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+
+	objectSet := &GenericObjectSet{
+		corev1alpha1.ObjectSet{
+			Spec: corev1alpha1.ObjectSetSpec{
+				ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+					Phases: []corev1alpha1.ObjectSetTemplatePhase{
+						{Name: "phase-1", ParallelGroup: "a"},
+						{Name: "phase-2", ParallelGroup: "a"},
+					},
+				},
+			},
+		},
+	}
+	newManaged := func(name string) corev1alpha1.ObjectSetManagedObject {
+		return corev1alpha1.ObjectSetManagedObject{
+			ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+				Kind: "ConfigMap", Name: name, Namespace: "test",
+			},
+			Version:   "v1",
+			Available: true,
+		}
+	}
+	// Both phases record only once both are running.
+	var running sync.WaitGroup
+	running.Add(2)
+	recordManaged := func(names ...string) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			running.Done()
+			running.Wait()
+			owner := args.Get(1).(controllers.PhaseObjectOwner)
+			recorder := owner.(interface {
+				RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject)
+			})
+			for _, name := range names {
+				recorder.RecordManagedObject(newManaged(name))
+			}
+		}
+	}
+	pr.On("ReconcilePhase", mock.Anything, mock.Anything, phaseNamed("phase-1"), mock.Anything, mock.Anything).
+		Run(recordManaged("a", "b")).
+		Return([]string{}, nil)
+	pr.On("ReconcilePhase", mock.Anything, mock.Anything, phaseNamed("phase-2"), mock.Anything, mock.Anything).
+		Run(recordManaged("c", "d")).
+		Return([]string{}, nil)
+
+	_, err := r.Reconcile(context.Background(), objectSet)
+	require.NoError(t, err)
+
+	// Merged in phase order, regardless of which phase finished first.
+	assert.Equal(t, []corev1alpha1.ObjectSetManagedObject{
+		newManaged("a"), newManaged("b"), newManaged("c"), newManaged("d"),
+	}, objectSet.Status.ManagedObjects)
+}
+
+func Test_phasesReconciler_lookupPreviousRevisions(t *testing.T) {
+	c := testutil.NewClient()
+	r := newPhasesReconciler(c, nil, nil, testScheme, newGenericObjectSet, newGenericObjectSetPhase)
+
+	c.
+		On("Get", mock.Anything, client.ObjectKey{Name: "rev-1", Namespace: "test"},
+			mock.AnythingOfType("*v1alpha1.ObjectSet")).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*corev1alpha1.ObjectSet)
+			obj.Name = "rev-1"
+			obj.Spec.Phases = []corev1alpha1.ObjectSetTemplatePhase{
+				{Name: "local"},
+				{Name: "delegated", Class: "hosted-cluster"},
+				{Name: "torn-down", Class: "hosted-cluster"},
+			}
+		}).
+		Return(nil)
+	c.
+		On("Get", mock.Anything, client.ObjectKey{Name: "rev-1-delegated", Namespace: "test"},
+			mock.AnythingOfType("*v1alpha1.ObjectSetPhase")).
+		Run(func(args mock.Arguments) {
+			args.Get(2).(*corev1alpha1.ObjectSetPhase).Name = "rev-1-delegated"
+		}).
+		Return(nil)
+	c.
+		On("Get", mock.Anything, client.ObjectKey{Name: "rev-1-torn-down", Namespace: "test"},
+			mock.AnythingOfType("*v1alpha1.ObjectSetPhase")).
+		Return(apierrors.NewNotFound(schema.GroupResource{}, ""))
+
+	objectSet := &GenericObjectSet{
+		corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "rev-2", Namespace: "test"},
+			Spec: corev1alpha1.ObjectSetSpec{
+				Previous: []corev1alpha1.PreviousRevisionReference{{Name: "rev-1"}},
+			},
+		},
+	}
+	previous, err := r.lookupPreviousRevisions(context.Background(), objectSet)
+	require.NoError(t, err)
+
+	// Objects can be taken back from phases the previous revision delegated.
+	var names []string
+	for _, prev := range previous {
+		names = append(names, prev.GetName())
+	}
+	assert.Equal(t, []string{"rev-1", "rev-1-delegated"}, names)
+}
+
+func Test_parallelGroups(t *testing.T) {
+	groups := parallelGroups([]corev1alpha1.ObjectSetTemplatePhase{
+		{Name: "a"},
+		{Name: "b", ParallelGroup: "x"},
+		{Name: "c", ParallelGroup: "x"},
+		{Name: "d"},
+		{Name: "e"},
+		{Name: "f", ParallelGroup: "x"},
+	})
+
+	var names [][]string
+	for _, group := range groups {
+		var groupNames []string
+		for _, phase := range group {
+			groupNames = append(groupNames, phase.Name)
+		}
+		names = append(names, groupNames)
+	}
+	assert.Equal(t, [][]string{{"a"}, {"b", "c"}, {"d"}, {"e"}, {"f"}}, names)
+}
+
+func phaseNamed(name string) interface{} {
+	return mock.MatchedBy(func(phase corev1alpha1.ObjectSetTemplatePhase) bool {
+		return phase.Name == name
+	})
+}
+
+type phaseReconcilerMock struct {
+	mock.Mock
+}
+
+func (m *phaseReconcilerMock) ReconcilePhase(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+	probe probing.Prober, previous []client.Object,
+) ([]string, error) {
+	args := m.Called(ctx, owner, phase, probe, previous)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *phaseReconcilerMock) DetectPhaseDrift(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) ([]string, error) {
+	args := m.Called(ctx, owner, phase)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *phaseReconcilerMock) TeardownPhase(
+	ctx context.Context, owner controllers.PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (bool, error) {
+	args := m.Called(ctx, owner, phase)
+	return args.Bool(0), args.Error(1)
+}
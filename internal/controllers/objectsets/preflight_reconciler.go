@@ -0,0 +1,241 @@
+package objectsets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+)
+
+// RBAC changes don't trigger reconciles, so missing permissions are re-checked periodically.
+const preflightReconcilerRequeueDelay = time.Minute
+
+// Verbs Package Operator needs to apply and teardown objects.
+// Objects are read with the identity of Package Operator itself,
+// only writes are impersonated, so read access is not checked.
+var preflightVerbs = []string{
+	"create", "update", "patch", "delete",
+}
+
+// preflightReconciler checks that Package Operator is allowed to manage
+// all objects of a revision before anything is applied,
+// reporting every missing permission at once.
+// It also reports pre-existing objects that are going to be adopted.
+type preflightReconciler struct {
+	client         client.Writer
+	uncachedReader client.Reader
+	restMapper     meta.RESTMapper
+}
+
+type preflightAttributes struct {
+	group, resource, namespace, verb string
+}
+
+func (a preflightAttributes) String() string {
+	gr := a.resource
+	if len(a.group) > 0 {
+		gr += "." + a.group
+	}
+	if len(a.namespace) > 0 {
+		return fmt.Sprintf("%s %s in namespace %s", a.verb, gr, a.namespace)
+	}
+	return fmt.Sprintf("%s %s", a.verb, gr)
+}
+
+func (r *preflightReconciler) Reconcile(
+	ctx context.Context, objectSet genericObjectSet,
+) (res ctrl.Result, err error) {
+	if err := r.reportAdoptionCandidates(ctx, objectSet); err != nil {
+		return res, err
+	}
+
+	if objectSet.IsPaused() {
+		// Nothing is applied while paused.
+		return
+	}
+
+	cond := meta.FindStatusCondition(
+		*objectSet.GetConditions(), corev1alpha1.ObjectSetInsufficientPermissions)
+	if cond != nil && cond.Status == metav1.ConditionFalse &&
+		cond.ObservedGeneration == objectSet.ClientObject().GetGeneration() {
+		// Permissions have already been verified for this revision.
+		return
+	}
+
+	attributes, unmapped, err := r.requiredAttributes(objectSet)
+	if err != nil {
+		return res, err
+	}
+
+	// Check the permissions of the ServiceAccount objects are managed with.
+	ctx = controllers.WithOwnerServiceAccount(ctx, objectSet)
+	var missing []string
+	for _, attr := range attributes {
+		ssar := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:     attr.group,
+					Resource:  attr.resource,
+					Namespace: attr.namespace,
+					Verb:      attr.verb,
+				},
+			},
+		}
+		if err := r.client.Create(ctx, ssar); err != nil {
+			return res, fmt.Errorf("creating SelfSubjectAccessReview: %w", err)
+		}
+		if !ssar.Status.Allowed {
+			missing = append(missing, attr.String())
+		}
+	}
+
+	if len(missing) > 0 {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetInsufficientPermissions,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MissingPermissions",
+			Message:            "Missing permissions: " + strings.Join(missing, ", "),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return ctrl.Result{RequeueAfter: preflightReconcilerRequeueDelay}, nil
+	}
+	if unmapped {
+		// Some kinds are not yet known to the API server,
+		// e.g. CRDs created by an earlier phase. Check again on the next reconcile.
+		meta.RemoveStatusCondition(
+			objectSet.GetConditions(), corev1alpha1.ObjectSetInsufficientPermissions)
+		return
+	}
+
+	meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+		Type:               corev1alpha1.ObjectSetInsufficientPermissions,
+		Status:             metav1.ConditionFalse,
+		Reason:             "PermissionsVerified",
+		Message:            "All permissions required to manage objects are granted.",
+		ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+	})
+	return
+}
+
+// Reports pre-existing objects without a controller matching .spec.adoptObjects.
+// Runs while paused too, so adoptions can be reviewed before they happen.
+// Objects are looked up uncached, so this stops after the first successful rollout,
+// when every object is controlled by the ObjectSet and nothing is left to adopt.
+func (r *preflightReconciler) reportAdoptionCandidates(
+	ctx context.Context, objectSet genericObjectSet,
+) error {
+	objectSet.SetAdoptionCandidates(nil)
+	if objectSet.GetAdoptObjectsSelector() == nil ||
+		meta.IsStatusConditionTrue(*objectSet.GetConditions(), corev1alpha1.ObjectSetSucceeded) {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(objectSet.GetAdoptObjectsSelector())
+	if err != nil {
+		return fmt.Errorf("parsing adoption selector: %w", err)
+	}
+
+	var candidates []corev1alpha1.ObjectSetObjectReference
+	for _, phase := range objectSet.GetPhases() {
+		if len(phase.Class) > 0 {
+			// Remote phases are reconciled by another controller.
+			continue
+		}
+
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			}
+			if len(obj.GetNamespace()) == 0 {
+				obj.SetNamespace(objectSet.ClientObject().GetNamespace())
+			}
+
+			err := r.uncachedReader.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				// Nothing to adopt.
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("getting %s: %w", obj.GroupVersionKind(), err)
+			}
+			if metav1.GetControllerOf(obj) == nil &&
+				selector.Matches(labels.Set(obj.GetLabels())) {
+				candidates = append(candidates, newObjectReference(obj))
+			}
+		}
+	}
+	objectSet.SetAdoptionCandidates(candidates)
+	return nil
+}
+
+// Returns the deduplicated and sorted list of access checks
+// needed for all objects in phases reconciled by this cluster.
+// unmapped is true, if some objects were skipped because their kind is not yet registered.
+func (r *preflightReconciler) requiredAttributes(
+	objectSet genericObjectSet,
+) (attributes []preflightAttributes, unmapped bool, err error) {
+	seen := map[preflightAttributes]struct{}{}
+	for _, phase := range objectSet.GetPhases() {
+		if len(phase.Class) > 0 {
+			// Remote phases are reconciled by another controller.
+			continue
+		}
+
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return nil, false, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			}
+
+			gvk := obj.GroupVersionKind()
+			mapping, err := r.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if meta.IsNoMatchError(err) {
+				unmapped = true
+				continue
+			}
+			if err != nil {
+				return nil, false, fmt.Errorf("mapping %s: %w", gvk, err)
+			}
+
+			var namespace string
+			if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+				namespace = obj.GetNamespace()
+				if len(namespace) == 0 {
+					namespace = objectSet.ClientObject().GetNamespace()
+				}
+			}
+
+			for _, verb := range preflightVerbs {
+				seen[preflightAttributes{
+					group:     mapping.Resource.Group,
+					resource:  mapping.Resource.Resource,
+					namespace: namespace,
+					verb:      verb,
+				}] = struct{}{}
+			}
+		}
+	}
+
+	attributes = make([]preflightAttributes, 0, len(seen))
+	for attr := range seen {
+		attributes = append(attributes, attr)
+	}
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].String() < attributes[j].String()
+	})
+	return attributes, unmapped, nil
+}
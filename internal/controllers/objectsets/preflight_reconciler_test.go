@@ -0,0 +1,225 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func Test_preflightReconciler(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{
+		Version: "v1", Kind: "ConfigMap",
+	}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{
+		Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole",
+	}, meta.RESTScopeRoot)
+
+	newObjectSet := func() *GenericObjectSet {
+		return &GenericObjectSet{corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Generation: 1},
+			Spec: corev1alpha1.ObjectSetSpec{
+				ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+					Phases: []corev1alpha1.ObjectSetTemplatePhase{
+						{
+							Name: "phase-1",
+							Objects: []corev1alpha1.ObjectSetObject{
+								{Object: runtime.RawExtension{
+									Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`),
+								}},
+								{Object: runtime.RawExtension{
+									Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"cr"}}`),
+								}},
+							},
+						},
+						{
+							Name:  "remote",
+							Class: "hosted-cluster",
+							Objects: []corev1alpha1.ObjectSetObject{
+								{Object: runtime.RawExtension{
+									Raw: []byte(`{"apiVersion":"example.com/v1","kind":"Unknown","metadata":{"name":"x"}}`),
+								}},
+							},
+						},
+					},
+				},
+			},
+		}}
+	}
+
+	t.Run("reports all missing permissions", func(t *testing.T) {
+		c := testutil.NewClient()
+		var reviews []authorizationv1.ResourceAttributes
+		c.On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				ssar := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+				reviews = append(reviews, *ssar.Spec.ResourceAttributes)
+				ssar.Status.Allowed = ssar.Spec.ResourceAttributes.Resource == "configmaps" ||
+					ssar.Spec.ResourceAttributes.Verb != "delete"
+			}).
+			Return(nil)
+
+		r := &preflightReconciler{client: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+
+		assert.Equal(t, preflightReconcilerRequeueDelay, res.RequeueAfter)
+		assert.Len(t, reviews, 2*len(preflightVerbs))
+		assert.Contains(t, reviews, authorizationv1.ResourceAttributes{
+			Resource: "configmaps", Namespace: "test", Verb: "create",
+		})
+		// Reads are not impersonated.
+		assert.NotContains(t, reviews, authorizationv1.ResourceAttributes{
+			Resource: "configmaps", Namespace: "test", Verb: "get",
+		})
+
+		cond := meta.FindStatusCondition(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetInsufficientPermissions)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t,
+			"Missing permissions: delete clusterroles.rbac.authorization.k8s.io", cond.Message)
+	})
+
+	t.Run("verifies once per generation", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				ssar := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+				ssar.Status.Allowed = true
+			}).
+			Return(nil)
+
+		r := &preflightReconciler{client: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+
+		cond := meta.FindStatusCondition(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetInsufficientPermissions)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+
+		_, err = r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		c.AssertNumberOfCalls(t, "Create", 2*len(preflightVerbs))
+	})
+
+	t.Run("skips unregistered kinds", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Create", mock.Anything, mock.AnythingOfType("*v1.SelfSubjectAccessReview"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				ssar := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+				ssar.Status.Allowed = true
+			}).
+			Return(nil)
+
+		r := &preflightReconciler{client: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		objectSet.Spec.Phases[1].Class = ""
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+
+		assert.Nil(t, meta.FindStatusCondition(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetInsufficientPermissions))
+	})
+
+	t.Run("reports adoption candidates while paused", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.SetLabels(map[string]string{"app": "example"})
+				if obj.GetKind() == "ClusterRole" {
+					obj.SetOwnerReferences([]metav1.OwnerReference{
+						{Kind: "ObjectSet", Name: "other", Controller: pointer.Bool(true)},
+					})
+				}
+			}).
+			Return(nil)
+
+		r := &preflightReconciler{client: c, uncachedReader: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		objectSet.Spec.LifecycleState = corev1alpha1.ObjectSetLifecycleStatePaused
+		objectSet.Spec.AdoptObjects = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "example"},
+		}
+		_, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+
+		// The ClusterRole is controlled by someone else and can't be adopted.
+		assert.Equal(t, []corev1alpha1.ObjectSetObjectReference{
+			{Kind: "ConfigMap", Name: "cm", Namespace: "test"},
+		}, objectSet.Status.AdoptionCandidates)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("clears adoption candidates once adopted", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.SetLabels(map[string]string{"app": "example"})
+				obj.SetOwnerReferences([]metav1.OwnerReference{
+					{Kind: "ObjectSet", Name: "test", Controller: pointer.Bool(true)},
+				})
+			}).
+			Return(nil)
+
+		r := &preflightReconciler{client: c, uncachedReader: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		objectSet.Spec.LifecycleState = corev1alpha1.ObjectSetLifecycleStatePaused
+		objectSet.Spec.AdoptObjects = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "example"},
+		}
+		objectSet.Status.AdoptionCandidates = []corev1alpha1.ObjectSetObjectReference{
+			{Kind: "ConfigMap", Name: "cm", Namespace: "test"},
+		}
+		original := objectSet.ClientObject().DeepCopyObject().(client.Object)
+		_, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.Nil(t, objectSet.Status.AdoptionCandidates)
+
+		// The list has to be removed from the server as well.
+		patch, err := controllers.StatusPatch(original).Data(objectSet.ClientObject())
+		require.NoError(t, err)
+		assert.Contains(t, string(patch), `"adoptionCandidates":null`)
+	})
+
+	t.Run("skips adoption candidates after first successful rollout", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &preflightReconciler{client: c, uncachedReader: c, restMapper: restMapper}
+		objectSet := newObjectSet()
+		objectSet.Spec.LifecycleState = corev1alpha1.ObjectSetLifecycleStatePaused
+		objectSet.Spec.AdoptObjects = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "example"},
+		}
+		meta.SetStatusCondition(&objectSet.Status.Conditions, metav1.Condition{
+			Type:   corev1alpha1.ObjectSetSucceeded,
+			Status: metav1.ConditionTrue,
+			Reason: "RolloutSuccess",
+		})
+		_, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.Nil(t, objectSet.Status.AdoptionCandidates)
+		c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,7 +14,18 @@ import (
 
 const revisionReconcilerRequeueDelay = 10 * time.Second
 
-// revisionReconciler determines the .status.revision number by checking previous revisions.
+// PreviousRevisionNotFoundError is returned when a previous revision
+// referenced by an ObjectSet does not exist.
+type PreviousRevisionNotFoundError struct {
+	PreviousRevisionKey client.ObjectKey
+}
+
+func (e PreviousRevisionNotFoundError) Error() string {
+	return fmt.Sprintf("previous revision %s not found", e.PreviousRevisionKey)
+}
+
+// revisionReconciler determines the .status.revision number by checking previous revisions
+// and records the changes compared to the latest previous revision.
 type revisionReconciler struct {
 	scheme       *runtime.Scheme
 	newObjectSet genericObjectSetFactory
@@ -28,21 +40,23 @@ func (r *revisionReconciler) Reconcile(
 		return
 	}
 
-	if len(objectSet.GetPrevious()) == 0 {
-		// no previous revision(s) specified, default to revision 1
-		objectSet.SetStatusRevision(1)
-		return
-	}
-
-	// Determine new revision number by inspecting previous revisions:
-	var latestPreviousRevision int64
+	// Determine new revision number by inspecting previous revisions,
+	// defaults to revision 1 if no previous revision(s) are specified:
+	var (
+		latestPreviousRevision int64
+		latestPrevious         genericObjectSet
+	)
 	for _, prev := range objectSet.GetPrevious() {
 		prevObjectSet := r.newObjectSet(r.scheme)
 		key := client.ObjectKey{
 			Name:      prev.Name,
 			Namespace: objectSet.ClientObject().GetNamespace(),
 		}
-		if err := r.client.Get(ctx, key, prevObjectSet.ClientObject()); err != nil {
+		err = r.client.Get(ctx, key, prevObjectSet.ClientObject())
+		if errors.IsNotFound(err) {
+			return res, PreviousRevisionNotFoundError{PreviousRevisionKey: key}
+		}
+		if err != nil {
 			return res, fmt.Errorf("getting previous revision: %w", err)
 		}
 
@@ -59,9 +73,15 @@ func (r *revisionReconciler) Reconcile(
 
 		if sr > latestPreviousRevision {
 			latestPreviousRevision = sr
+			latestPrevious = prevObjectSet
 		}
 	}
 
+	changes, err := objectSetChanges(objectSet, latestPrevious)
+	if err != nil {
+		return res, fmt.Errorf("recording changes: %w", err)
+	}
+	objectSet.SetChanges(changes)
 	objectSet.SetStatusRevision(latestPreviousRevision + 1)
 	return
 }
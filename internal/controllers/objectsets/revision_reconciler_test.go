@@ -7,8 +7,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
@@ -159,4 +161,39 @@ func Test_revisionReconciler(t *testing.T) {
 		assert.False(t, res.IsZero())
 		assert.Equal(t, int64(0), objectSet.Status.Revision)
 	})
+
+	t.Run("previous not found", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		r := &revisionReconciler{
+			scheme:       testScheme,
+			newObjectSet: newGenericObjectSet,
+			client:       testClient,
+		}
+
+		testClient.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.NewNotFound(schema.GroupResource{}, ""))
+
+		objectSet := &GenericObjectSet{
+			corev1alpha1.ObjectSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "xxx",
+				},
+				Spec: corev1alpha1.ObjectSetSpec{
+					Previous: []corev1alpha1.PreviousRevisionReference{
+						{
+							Name: "prev1",
+						},
+					},
+				},
+			},
+		}
+
+		ctx := context.Background()
+		_, err := r.Reconcile(ctx, objectSet)
+		assert.Equal(t, PreviousRevisionNotFoundError{
+			PreviousRevisionKey: client.ObjectKey{Name: "prev1", Namespace: "xxx"},
+		}, err)
+		assert.Equal(t, int64(0), objectSet.Status.Revision)
+	})
 }
@@ -0,0 +1,97 @@
+package objectsets
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/jsonpath"
+)
+
+// statusCollectionReconciler copies fields of objects
+// as configured in .spec.statusCollection into .status.collectedStatus.
+type statusCollectionReconciler struct {
+	dynamicCache client.Reader
+}
+
+func (r *statusCollectionReconciler) Reconcile(
+	ctx context.Context, objectSet genericObjectSet,
+) (res ctrl.Result, err error) {
+	statusCollection := objectSet.GetStatusCollection()
+	if len(statusCollection) == 0 {
+		objectSet.SetCollectedStatus(nil)
+		return
+	}
+
+	collected := map[string]string{}
+	for _, sc := range statusCollection {
+		value, ok, err := r.collect(ctx, objectSet, sc)
+		if err != nil {
+			return res, fmt.Errorf("collecting status %q: %w", sc.Name, err)
+		}
+		if ok {
+			collected[sc.Name] = value
+		}
+	}
+	objectSet.SetCollectedStatus(collected)
+	return
+}
+
+// Returns the value of the referenced field or false,
+// if the object or field does not exist (yet).
+func (r *statusCollectionReconciler) collect(
+	ctx context.Context, objectSet genericObjectSet,
+	sc corev1alpha1.ObjectSetStatusCollection,
+) (value string, ok bool, err error) {
+	obj, err := findLocalPhaseObject(objectSet, sc.ObjectRef)
+	if err != nil || obj == nil {
+		return "", false, err
+	}
+
+	if err := r.dynamicCache.Get(
+		ctx, client.ObjectKeyFromObject(obj), obj); errors.IsNotFound(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	return jsonpath.Get(obj.Object, sc.FieldPath)
+}
+
+// Looks up the referenced object within phases that are reconciled in-process.
+// Objects of phases with a class set may live on another cluster and are not considered.
+func findLocalPhaseObject(
+	objectSet genericObjectSet, ref corev1alpha1.StatusCollectionObjectReference,
+) (*unstructured.Unstructured, error) {
+	for _, phase := range objectSet.GetPhases() {
+		if len(phase.Class) > 0 {
+			continue
+		}
+
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			// Warning!
+			// This MUST absolutely use sigs.k8s.io/yaml
+			// Any other yaml parser, might yield unexpected results.
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			}
+
+			gvk := obj.GroupVersionKind()
+			if gvk.Group != ref.Group || gvk.Kind != ref.Kind || obj.GetName() != ref.Name {
+				continue
+			}
+			if len(obj.GetNamespace()) == 0 {
+				obj.SetNamespace(objectSet.ClientObject().GetNamespace())
+			}
+			return obj, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,146 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func Test_statusCollectionReconciler(t *testing.T) {
+	newObjectSet := func() *GenericObjectSet {
+		return &GenericObjectSet{
+			corev1alpha1.ObjectSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "xxx",
+				},
+				Spec: corev1alpha1.ObjectSetSpec{
+					ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+						Phases: []corev1alpha1.ObjectSetTemplatePhase{
+							{
+								Name: "deploy",
+								Objects: []corev1alpha1.ObjectSetObject{
+									{
+										Object: runtime.RawExtension{
+											Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"operator"}}`),
+										},
+									},
+								},
+							},
+						},
+						StatusCollection: []corev1alpha1.ObjectSetStatusCollection{
+							{
+								Name:      "version",
+								FieldPath: ".status.version",
+								ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+									Group: "apps", Kind: "Deployment", Name: "operator",
+								},
+							},
+							{
+								Name:      "replicas",
+								FieldPath: ".status.replicas",
+								ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+									Group: "apps", Kind: "Deployment", Name: "operator",
+								},
+							},
+							{
+								Name:      "available",
+								FieldPath: `.status.conditions[?(@.type=="Available")].status`,
+								ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+									Group: "apps", Kind: "Deployment", Name: "operator",
+								},
+							},
+							{
+								Name:      "missing",
+								FieldPath: ".status.missing",
+								ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+									Group: "apps", Kind: "Deployment", Name: "operator",
+								},
+							},
+							{
+								Name:      "not-part-of-objectset",
+								FieldPath: ".status.version",
+								ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+									Group: "apps", Kind: "Deployment", Name: "other",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("collects fields", func(t *testing.T) {
+		dc := testutil.NewClient()
+		r := &statusCollectionReconciler{dynamicCache: dc}
+
+		dc.
+			On("Get", mock.Anything, client.ObjectKey{
+				Name: "operator", Namespace: "xxx",
+			}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.Object["status"] = map[string]interface{}{
+					"version":  "v1.2.3",
+					"replicas": int64(3),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": "True"},
+					},
+				}
+			}).
+			Return(nil)
+
+		objectSet := newObjectSet()
+		ctx := context.Background()
+		res, err := r.Reconcile(ctx, objectSet)
+		require.NoError(t, err)
+
+		assert.True(t, res.IsZero(), "unexpected requeue")
+		assert.Equal(t, map[string]string{
+			"version":   "v1.2.3",
+			"replicas":  "3",
+			"available": "True",
+		}, objectSet.Status.CollectedStatus)
+	})
+
+	t.Run("object not created yet", func(t *testing.T) {
+		dc := testutil.NewClient()
+		r := &statusCollectionReconciler{dynamicCache: dc}
+
+		dc.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.NewNotFound(schema.GroupResource{}, ""))
+
+		objectSet := newObjectSet()
+		ctx := context.Background()
+		_, err := r.Reconcile(ctx, objectSet)
+		require.NoError(t, err)
+
+		assert.Empty(t, objectSet.Status.CollectedStatus)
+	})
+
+	t.Run("clears collected status", func(t *testing.T) {
+		dc := testutil.NewClient()
+		r := &statusCollectionReconciler{dynamicCache: dc}
+
+		objectSet := &GenericObjectSet{}
+		objectSet.Status.CollectedStatus = map[string]string{"a": "b"}
+		ctx := context.Background()
+		_, err := r.Reconcile(ctx, objectSet)
+		require.NoError(t, err)
+
+		assert.Nil(t, objectSet.Status.CollectedStatus)
+	})
+}
@@ -0,0 +1,119 @@
+package objectsets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+)
+
+// Namespace annotation changes don't trigger reconciles, so violations are re-checked periodically.
+const tenantIsolationReconcilerRequeueDelay = time.Minute
+
+// tenantIsolationReconciler restricts namespaced ObjectSets to objects within their own namespace,
+// when tenant isolation is enforced operator-wide or by annotating the namespace.
+type tenantIsolationReconciler struct {
+	client     client.Reader
+	restMapper meta.RESTMapper
+	// Enforce tenant isolation for all namespaces.
+	enforced bool
+}
+
+func (r *tenantIsolationReconciler) Reconcile(
+	ctx context.Context, objectSet genericObjectSet,
+) (res ctrl.Result, err error) {
+	namespace := objectSet.ClientObject().GetNamespace()
+	if len(namespace) == 0 {
+		// ClusterObjectSets are created by cluster admins.
+		return
+	}
+
+	isolated, err := r.isolated(ctx, namespace)
+	if err != nil {
+		return res, err
+	}
+	if !isolated {
+		meta.RemoveStatusCondition(
+			objectSet.GetConditions(), corev1alpha1.ObjectSetPolicyViolation)
+		return
+	}
+
+	violations, err := r.violations(objectSet)
+	if err != nil {
+		return res, err
+	}
+	if len(violations) > 0 {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               corev1alpha1.ObjectSetPolicyViolation,
+			Status:             metav1.ConditionTrue,
+			Reason:             "TenantIsolation",
+			Message:            "Objects must be namespaced and in namespace " + namespace + ": " + strings.Join(violations, ", "),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return ctrl.Result{RequeueAfter: tenantIsolationReconcilerRequeueDelay}, nil
+	}
+
+	meta.RemoveStatusCondition(
+		objectSet.GetConditions(), corev1alpha1.ObjectSetPolicyViolation)
+	return
+}
+
+func (r *tenantIsolationReconciler) isolated(
+	ctx context.Context, namespace string,
+) (bool, error) {
+	if r.enforced {
+		return true, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, fmt.Errorf("getting namespace: %w", err)
+	}
+	return ns.Annotations[controllers.TenantIsolationAnnotation] == "true", nil
+}
+
+// Returns a description of every object that is cluster-scoped or outside of the ObjectSet namespace.
+func (r *tenantIsolationReconciler) violations(
+	objectSet genericObjectSet,
+) (violations []string, err error) {
+	namespace := objectSet.ClientObject().GetNamespace()
+	for _, phase := range objectSet.GetPhases() {
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			}
+
+			gvk := obj.GroupVersionKind()
+			ref := fmt.Sprintf("%s %s", gvk.GroupKind(), obj.GetName())
+			mapping, err := r.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if meta.IsNoMatchError(err) {
+				// Without a mapping we can't tell whether this object will stay in its namespace.
+				violations = append(violations, ref+" (unknown kind)")
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("mapping %s: %w", gvk, err)
+			}
+
+			switch {
+			case mapping.Scope.Name() != meta.RESTScopeNameNamespace:
+				violations = append(violations, ref+" (cluster-scoped)")
+			case len(obj.GetNamespace()) > 0 && obj.GetNamespace() != namespace:
+				violations = append(violations, ref+" (in namespace "+obj.GetNamespace()+")")
+			}
+		}
+	}
+	return violations, nil
+}
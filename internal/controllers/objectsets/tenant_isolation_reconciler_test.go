@@ -0,0 +1,111 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func Test_tenantIsolationReconciler(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{
+		Version: "v1", Kind: "ConfigMap",
+	}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{
+		Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole",
+	}, meta.RESTScopeRoot)
+
+	newObjectSet := func(objects ...string) *GenericObjectSet {
+		phase := corev1alpha1.ObjectSetTemplatePhase{Name: "phase-1"}
+		for _, obj := range objects {
+			phase.Objects = append(phase.Objects, corev1alpha1.ObjectSetObject{
+				Object: runtime.RawExtension{Raw: []byte(obj)},
+			})
+		}
+		return &GenericObjectSet{corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "tenant"},
+			Spec: corev1alpha1.ObjectSetSpec{
+				ObjectSetTemplateSpec: corev1alpha1.ObjectSetTemplateSpec{
+					Phases: []corev1alpha1.ObjectSetTemplatePhase{phase},
+				},
+			},
+		}}
+	}
+
+	t.Run("reports violations when enforced", func(t *testing.T) {
+		r := &tenantIsolationReconciler{
+			client: testutil.NewClient(), restMapper: restMapper, enforced: true,
+		}
+		objectSet := newObjectSet(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"ok"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"same","namespace":"tenant"}}`,
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"other","namespace":"kube-system"}}`,
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"cr"}}`,
+			`{"apiVersion":"example.com/v1","kind":"Unknown","metadata":{"name":"x"}}`,
+		)
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.Equal(t, tenantIsolationReconcilerRequeueDelay, res.RequeueAfter)
+
+		cond := meta.FindStatusCondition(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetPolicyViolation)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "Objects must be namespaced and in namespace tenant: "+
+			"ConfigMap other (in namespace kube-system), "+
+			"ClusterRole.rbac.authorization.k8s.io cr (cluster-scoped), "+
+			"Unknown.example.com x (unknown kind)", cond.Message)
+	})
+
+	t.Run("enabled by namespace annotation", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "tenant"}, mock.AnythingOfType("*v1.Namespace")).
+			Run(func(args mock.Arguments) {
+				ns := args.Get(2).(*corev1.Namespace)
+				ns.Annotations = map[string]string{controllers.TenantIsolationAnnotation: "true"}
+			}).
+			Return(nil)
+
+		r := &tenantIsolationReconciler{client: c, restMapper: restMapper}
+		objectSet := newObjectSet(
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"cr"}}`,
+		)
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.False(t, res.IsZero())
+		assert.True(t, meta.IsStatusConditionTrue(
+			objectSet.Status.Conditions, corev1alpha1.ObjectSetPolicyViolation))
+	})
+
+	t.Run("not isolated", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "tenant"}, mock.AnythingOfType("*v1.Namespace")).
+			Return(nil)
+
+		r := &tenantIsolationReconciler{client: c, restMapper: restMapper}
+		objectSet := newObjectSet(
+			`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"cr"}}`,
+		)
+		meta.SetStatusCondition(&objectSet.Status.Conditions, metav1.Condition{
+			Type:   corev1alpha1.ObjectSetPolicyViolation,
+			Status: metav1.ConditionTrue,
+		})
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+		assert.Empty(t, objectSet.Status.Conditions)
+	})
+}
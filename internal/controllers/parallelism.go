@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// GroupKindLimits maps GroupKinds to a maximum number.
+// Implements flag.Value, parsing a list like "CustomResourceDefinition.apiextensions.k8s.io=1".
+type GroupKindLimits map[schema.GroupKind]int
+
+func (l GroupKindLimits) String() string {
+	pairs := make([]string, 0, len(l))
+	for gk, limit := range l {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", gk, limit))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (l GroupKindLimits) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid limit %q, expected Kind.group=N", pair)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || limit < 1 {
+			return fmt.Errorf("invalid limit %q, expected a positive number", pair)
+		}
+		l[schema.ParseGroupKind(strings.TrimSpace(parts[0]))] = limit
+	}
+	return nil
+}
+
+// ParallelismLimiter caps the number of concurrent writes per GroupKind.
+// Kinds without a limit are not capped.
+type ParallelismLimiter struct {
+	slots map[schema.GroupKind]chan struct{}
+}
+
+func NewParallelismLimiter(limits GroupKindLimits) *ParallelismLimiter {
+	slots := map[schema.GroupKind]chan struct{}{}
+	for gk, limit := range limits {
+		slots[gk] = make(chan struct{}, limit)
+	}
+	return &ParallelismLimiter{slots: slots}
+}
+
+// Waits for a free slot of the given GroupKind.
+// The returned func must be called to free the slot again.
+func (l *ParallelismLimiter) acquire(
+	ctx context.Context, gk schema.GroupKind,
+) (release func(), err error) {
+	slots, ok := l.slots[gk]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ParallelismLimitedClient waits for the limiter before every write,
+// so e.g. CustomResourceDefinitions are created one at a time,
+// while other objects are still written in parallel.
+type ParallelismLimitedClient struct {
+	client.Client
+	limiter *ParallelismLimiter
+}
+
+// Creates a new ParallelismLimitedClient.
+// The limiter may be shared to cap the writes of multiple clients together.
+func NewParallelismLimitedClient(
+	c client.Client, limiter *ParallelismLimiter,
+) *ParallelismLimitedClient {
+	return &ParallelismLimitedClient{
+		Client:  c,
+		limiter: limiter,
+	}
+}
+
+func (c *ParallelismLimitedClient) acquire(
+	ctx context.Context, obj client.Object,
+) (release func(), err error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		// The write itself will fail with a better error.
+		return func() {}, nil //nolint:nilerr
+	}
+	return c.limiter.acquire(ctx, gvk.GroupKind())
+}
+
+func (c *ParallelismLimitedClient) Create(
+	ctx context.Context, obj client.Object, opts ...client.CreateOption,
+) error {
+	release, err := c.acquire(ctx, obj)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *ParallelismLimitedClient) Delete(
+	ctx context.Context, obj client.Object, opts ...client.DeleteOption,
+) error {
+	release, err := c.acquire(ctx, obj)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *ParallelismLimitedClient) Update(
+	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
+) error {
+	release, err := c.acquire(ctx, obj)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *ParallelismLimitedClient) Patch(
+	ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption,
+) error {
+	release, err := c.acquire(ctx, obj)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *ParallelismLimitedClient) DeleteAllOf(
+	ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption,
+) error {
+	release, err := c.acquire(ctx, obj)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
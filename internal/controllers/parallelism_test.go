@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestGroupKindLimits(t *testing.T) {
+	l := GroupKindLimits{}
+	require.NoError(t, l.Set("CustomResourceDefinition.apiextensions.k8s.io=1, Secret=5"))
+
+	assert.Equal(t, GroupKindLimits{
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: 1,
+		{Kind: "Secret"}: 5,
+	}, l)
+	assert.Equal(t, "CustomResourceDefinition.apiextensions.k8s.io=1,Secret=5", l.String())
+
+	assert.Error(t, l.Set("Secret"))
+	assert.Error(t, l.Set("Secret=0"))
+}
+
+func TestParallelismLimitedClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	wrapped := testutil.NewClient()
+	limiter := NewParallelismLimiter(GroupKindLimits{{Kind: "ConfigMap"}: 1})
+	c := NewParallelismLimitedClient(wrapped, limiter)
+
+	wrapped.On("Scheme").Return(scheme)
+	wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// Occupy the only ConfigMap slot.
+	release, err := limiter.acquire(context.Background(), schema.GroupKind{Kind: "ConfigMap"})
+	require.NoError(t, err)
+
+	// Writes waiting for a slot are aborted with their context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, c.Create(ctx, &corev1.ConfigMap{}))
+	wrapped.AssertNumberOfCalls(t, "Create", 0)
+
+	// Other kinds are not limited.
+	require.NoError(t, c.Create(context.Background(), &corev1.Secret{}))
+	wrapped.AssertNumberOfCalls(t, "Create", 1)
+
+	release()
+	require.NoError(t, c.Create(context.Background(), &corev1.ConfigMap{}))
+	wrapped.AssertNumberOfCalls(t, "Create", 2)
+}
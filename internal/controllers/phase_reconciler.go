@@ -2,16 +2,22 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -27,15 +33,25 @@ type PhaseReconciler struct {
 	scheme *runtime.Scheme
 	// just specify a writer, because we don't want to ever read from another source than
 	// the dynamic cache that is managed to hold the objects we are reconciling.
-	writer          client.Writer
+	writer client.Writer
+	// reads objects that are not part of the dynamic cache: instances of CustomResourceDefinitions,
+	// external objects and objects annotated to bypass the cache.
+	uncachedReader  client.Reader
 	dynamicCache    dynamicCache
 	ownerStrategy   ownerStrategy
 	adoptionChecker adoptionChecker
 	patcher         patcher
+	metricsRecorder metricsRecorder
+	kindPolicy      KindPolicy
+	// remove Package Operator finalizers from objects stuck in deletion.
+	forceRemoveFinalizers bool
+	// time to wait after creating objects of these kinds, before reconciling further objects.
+	settleDelays GroupKindDurations
 }
 
 type ownerStrategy interface {
 	IsController(owner, obj metav1.Object) bool
+	HasController(obj metav1.Object) bool
 	ReleaseController(obj metav1.Object)
 	RemoveOwner(owner, obj metav1.Object)
 	SetControllerReference(owner, obj metav1.Object) error
@@ -52,7 +68,13 @@ type patcher interface {
 	Patch(
 		ctx context.Context,
 		desiredObj, currentObj, updatedObj *unstructured.Unstructured,
-	) error
+	) (patchNeeded bool, err error)
+}
+
+type metricsRecorder interface {
+	RecordObjectDriftDetected(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectDriftReverted(owner client.Object, gvk schema.GroupVersionKind)
+	RecordObjectConflict(owner client.Object, gvk schema.GroupVersionKind, manager string)
 }
 
 type dynamicCache interface {
@@ -65,16 +87,27 @@ type dynamicCache interface {
 func NewPhaseReconciler(
 	scheme *runtime.Scheme,
 	writer client.Writer,
+	uncachedReader client.Reader,
 	dynamicCache dynamicCache,
 	ownerStrategy ownerStrategy,
+	metricsRecorder metricsRecorder,
+	kindPolicy KindPolicy,
+	forceRemoveFinalizers bool,
+	settleDelays GroupKindDurations,
 ) *PhaseReconciler {
 	return &PhaseReconciler{
 		scheme:          scheme,
 		writer:          writer,
+		uncachedReader:  uncachedReader,
 		dynamicCache:    dynamicCache,
 		ownerStrategy:   ownerStrategy,
 		adoptionChecker: &defaultAdoptionChecker{ownerStrategy: ownerStrategy},
 		patcher:         &defaultPatcher{writer: writer},
+		metricsRecorder: metricsRecorder,
+		kindPolicy:      kindPolicy,
+
+		forceRemoveFinalizers: forceRemoveFinalizers,
+		settleDelays:          settleDelays,
 	}
 }
 
@@ -84,36 +117,191 @@ type PhaseObjectOwner interface {
 	IsPaused() bool
 }
 
+// Implemented by owners reporting objects stuck in deletion during teardown.
+type stuckObjectsRecorder interface {
+	RecordStuckObject(obj client.Object)
+}
+
+// Implemented by owners reporting objects orphaned during teardown.
+type orphanedObjectsRecorder interface {
+	RecordOrphanedObject(obj client.Object)
+}
+
+// Implemented by owners reporting the objects they control.
+type controlledObjectsRecorder interface {
+	RecordControlledObject(obj client.Object)
+}
+
+// Implemented by owners reporting the objects they manage and their health.
+type managedObjectsRecorder interface {
+	RecordManagedObject(managed corev1alpha1.ObjectSetManagedObject)
+}
+
+// Implemented by owners adopting pre-existing objects without a controller.
+type adoptObjectsOwner interface {
+	GetAdoptObjectsSelector() *metav1.LabelSelector
+}
+
 func (r *PhaseReconciler) ReconcilePhase(
 	ctx context.Context, owner PhaseObjectOwner,
 	phase corev1alpha1.ObjectSetTemplatePhase,
 	probe probing.Prober, previous []client.Object,
 ) (failedProbes []string, err error) {
+	ctx = WithOwnerServiceAccount(ctx, owner)
 
-	for _, phaseObject := range phase.Objects {
+	// Check all objects upfront, so the phase isn't applied partially.
+	weights := make([]int, len(phase.Objects))
+	for i, phaseObject := range phase.Objects {
+		obj, err := unstructuredFromObjectSetObject(&phaseObject)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.kindPolicy.Check(obj.GroupVersionKind().GroupKind()); err != nil {
+			return nil, err
+		}
+		if phase.Ordered {
+			if weights[i], err = objectWeight(obj); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Wait for external objects, before reconciling objects that may depend on them.
+	failedProbes, err = r.checkExternalObjects(ctx, owner, phase)
+	if err != nil || len(failedProbes) > 0 {
+		return failedProbes, err
+	}
+
+	phaseObjects := phase.Objects
+	if phase.Ordered {
+		phaseObjects = sortByWeight(phase.Objects, weights)
+	}
+	for _, phaseObject := range phaseObjects {
 		actualObj, err := r.reconcilePhaseObject(ctx, owner, phaseObject, previous)
 		if err != nil {
 			return nil, err
 		}
+		if actualObj == nil {
+			// Missing while paused.
+			failedProbes = append(failedProbes, missingPhaseObjectMessage(owner, phaseObject))
+			if phase.Ordered {
+				return failedProbes, nil
+			}
+			continue
+		}
+		if err := r.checkSettled(actualObj); err != nil {
+			return nil, err
+		}
+		isController := r.ownerStrategy.IsController(owner.ClientObject(), actualObj)
+		if recorder, ok := owner.(controlledObjectsRecorder); ok && isController {
+			recorder.RecordControlledObject(actualObj)
+		}
 
-		if success, message := probe.Probe(actualObj); !success {
+		success, message := probe.Probe(actualObj)
+		if recorder, ok := owner.(managedObjectsRecorder); ok && isController {
+			recorder.RecordManagedObject(
+				newManagedObject(phaseObject, actualObj, success, message))
+		}
+		if !success {
 			gvk := actualObj.GroupVersionKind()
 			failedProbes = append(failedProbes,
 				fmt.Sprintf("%s %s %s/%s: %s",
 					gvk.Group, gvk.Kind, actualObj.GetNamespace(), actualObj.GetName(), message))
+			if phase.Ordered {
+				// following objects wait for this one.
+				return failedProbes, nil
+			}
 		}
 	}
 
 	return
 }
 
+// Describes an object of a phase, that is missing from the cluster
+// in the same format as failed probes.
+func missingPhaseObjectMessage(
+	owner PhaseObjectOwner, phaseObject corev1alpha1.ObjectSetObject,
+) string {
+	// Objects have already been decoded successfully in ReconcilePhase.
+	obj, _ := unstructuredFromObjectSetObject(&phaseObject)
+	namespace := obj.GetNamespace()
+	if len(namespace) == 0 {
+		namespace = owner.ClientObject().GetNamespace()
+	}
+	gvk := obj.GroupVersionKind()
+	return fmt.Sprintf("%s %s %s/%s: missing", gvk.Group, gvk.Kind, namespace, obj.GetName())
+}
+
+// Compares objects of a phase with their state on the cluster without changing anything,
+// so drift can be reported while the owner is paused.
+// Returns a description of every object that would be created, adopted or patched.
+func (r *PhaseReconciler) DetectPhaseDrift(
+	ctx context.Context, owner PhaseObjectOwner,
+	phase corev1alpha1.ObjectSetTemplatePhase,
+) (drifted []string, err error) {
+	for _, phaseObject := range phase.Objects {
+		desiredObj, err := r.desiredObject(ctx, owner, phaseObject)
+		if err != nil {
+			return nil, fmt.Errorf("building desired object: %w", err)
+		}
+		gvk := desiredObj.GroupVersionKind()
+		ref := fmt.Sprintf("%s %s/%s", gvk.GroupKind(), desiredObj.GetNamespace(), desiredObj.GetName())
+
+		currentObj := desiredObj.DeepCopy()
+		err = r.readerFor(desiredObj).Get(ctx, client.ObjectKeyFromObject(desiredObj), currentObj)
+		if errors.IsNotFound(err) {
+			drifted = append(drifted, ref+" (missing)")
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting %s: %w", gvk, err)
+		}
+
+		if !r.ownerStrategy.IsController(owner.ClientObject(), currentObj) {
+			drifted = append(drifted, ref+" (not controlled)")
+			continue
+		}
+		_, patchNeeded := objectPatch(desiredObj, currentObj)
+		if patchNeeded ||
+			!containsKeys(currentObj.GetLabels(), desiredObj.GetLabels()) ||
+			!containsKeys(currentObj.GetAnnotations(), desiredObj.GetAnnotations()) {
+			drifted = append(drifted, ref)
+		}
+	}
+	return drifted, nil
+}
+
 func (r *PhaseReconciler) TeardownPhase(
 	ctx context.Context, owner PhaseObjectOwner,
 	phase corev1alpha1.ObjectSetTemplatePhase,
 ) (cleanupDone bool, err error) {
-	var cleanupCounter int
-	objectsToCleanup := len(phase.Objects)
+	ctx = WithOwnerServiceAccount(ctx, owner)
+
+	// CustomResourceDefinitions are torn down last,
+	// so instances of them in the same phase are gone,
+	// before the CRD deletion guard looks for remaining instances.
+	var objects, crds []corev1alpha1.ObjectSetObject
 	for _, phaseObject := range phase.Objects {
+		if isCRDObject(phaseObject) {
+			crds = append(crds, phaseObject)
+			continue
+		}
+		objects = append(objects, phaseObject)
+	}
+
+	done, err := r.teardownPhaseObjects(ctx, owner, objects)
+	if err != nil || !done {
+		return false, err
+	}
+	return r.teardownPhaseObjects(ctx, owner, crds)
+}
+
+func (r *PhaseReconciler) teardownPhaseObjects(
+	ctx context.Context, owner PhaseObjectOwner,
+	phaseObjects []corev1alpha1.ObjectSetObject,
+) (cleanupDone bool, err error) {
+	var cleanupCounter int
+	for _, phaseObject := range phaseObjects {
 		done, err := r.teardownPhaseObject(ctx, owner, phaseObject)
 		if err != nil {
 			return false, err
@@ -123,7 +311,17 @@ func (r *PhaseReconciler) TeardownPhase(
 			cleanupCounter++
 		}
 	}
-	return cleanupCounter == objectsToCleanup, nil
+	return cleanupCounter == len(phaseObjects), nil
+}
+
+// Returns true, if the phase object is a CustomResourceDefinition.
+func isCRDObject(phaseObject corev1alpha1.ObjectSetObject) bool {
+	obj, err := unstructuredFromObjectSetObject(&phaseObject)
+	if err != nil {
+		// Malformed objects fail teardown with a proper error.
+		return false
+	}
+	return obj.GroupVersionKind().GroupKind() == crdGroupKind
 }
 
 func (r *PhaseReconciler) teardownPhaseObject(
@@ -143,7 +341,7 @@ func (r *PhaseReconciler) teardownPhaseObject(
 	}
 
 	currentObj := desiredObj.DeepCopy()
-	err = r.dynamicCache.Get(
+	err = r.readerFor(desiredObj).Get(
 		ctx, client.ObjectKeyFromObject(desiredObj), currentObj)
 	if err != nil && errors.IsNotFound(err) {
 		// No matter who the owner of this object is,
@@ -154,6 +352,20 @@ func (r *PhaseReconciler) teardownPhaseObject(
 		return false, fmt.Errorf("getting object for teardown: %w", err)
 	}
 
+	if r.ownerStrategy.IsController(owner.ClientObject(), currentObj) &&
+		desiredObj.GetAnnotations()[TeardownPolicyAnnotation] == TeardownPolicyKeep {
+		// this object has to survive teardown,
+		// so we just release it.
+		r.ownerStrategy.RemoveOwner(owner.ClientObject(), currentObj)
+		if err := r.writer.Update(ctx, currentObj); err != nil {
+			return false, fmt.Errorf("orphaning object: %w", err)
+		}
+		if recorder, ok := owner.(orphanedObjectsRecorder); ok {
+			recorder.RecordOrphanedObject(currentObj)
+		}
+		return true, nil
+	}
+
 	if !r.ownerStrategy.IsController(owner.ClientObject(), currentObj) {
 		// this object is owned by someone else
 		// so we don't have to delete it for cleanup,
@@ -165,6 +377,10 @@ func (r *PhaseReconciler) teardownPhaseObject(
 		return true, nil
 	}
 
+	if err := r.checkCRDDeletion(ctx, desiredObj, currentObj); err != nil {
+		return false, err
+	}
+
 	err = r.writer.Delete(ctx, currentObj)
 	if err != nil && errors.IsNotFound(err) {
 		return true, nil
@@ -173,9 +389,52 @@ func (r *PhaseReconciler) teardownPhaseObject(
 		return false, fmt.Errorf("deleting object for teardown: %w", err)
 	}
 
+	if deletionTimestamp := currentObj.GetDeletionTimestamp(); deletionTimestamp != nil &&
+		time.Since(deletionTimestamp.Time) > StuckDeletionThreshold {
+		if err := r.handleStuckDeletion(ctx, owner, currentObj); err != nil {
+			return false, err
+		}
+	}
+
 	return false, nil
 }
 
+// Reports objects stuck in deletion and optionally removes Package Operator finalizers from them,
+// which may never be removed when the controller responsible is already gone.
+func (r *PhaseReconciler) handleStuckDeletion(
+	ctx context.Context, owner PhaseObjectOwner, currentObj *unstructured.Unstructured,
+) error {
+	if recorder, ok := owner.(stuckObjectsRecorder); ok {
+		recorder.RecordStuckObject(currentObj)
+	}
+	if !r.forceRemoveFinalizers {
+		return nil
+	}
+
+	var (
+		finalizers []string
+		removed    []string
+	)
+	for _, f := range currentObj.GetFinalizers() {
+		if strings.HasPrefix(f, finalizerPrefix) {
+			removed = append(removed, f)
+			continue
+		}
+		finalizers = append(finalizers, f)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	logr.FromContextOrDiscard(ctx).Info("removing finalizers from object stuck in deletion",
+		"object", client.ObjectKeyFromObject(currentObj), "kind", currentObj.GetKind(), "finalizers", removed)
+	currentObj.SetFinalizers(finalizers)
+	if err := r.writer.Update(ctx, currentObj); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("removing finalizers from object stuck in deletion: %w", err)
+	}
+	return nil
+}
+
 func (r *PhaseReconciler) reconcilePhaseObject(
 	ctx context.Context, owner PhaseObjectOwner,
 	phaseObject corev1alpha1.ObjectSetObject,
@@ -195,7 +454,12 @@ func (r *PhaseReconciler) reconcilePhaseObject(
 
 	if owner.IsPaused() {
 		actualObj = desiredObj.DeepCopy()
-		if err := r.dynamicCache.Get(ctx, client.ObjectKeyFromObject(desiredObj), actualObj); err != nil {
+		err := r.readerFor(desiredObj).Get(ctx, client.ObjectKeyFromObject(desiredObj), actualObj)
+		if errors.IsNotFound(err) {
+			// Nothing is created while paused, missing objects are reported as drift.
+			return nil, nil
+		}
+		if err != nil {
 			return nil, fmt.Errorf("looking up object while paused: %w", err)
 		}
 		return actualObj, nil
@@ -204,6 +468,15 @@ func (r *PhaseReconciler) reconcilePhaseObject(
 	return r.reconcileObject(ctx, owner, desiredObj, previous)
 }
 
+// Returns the reader to look up the given object with.
+// Objects annotated with CacheBypassAnnotation are read live.
+func (r *PhaseReconciler) readerFor(obj client.Object) client.Reader {
+	if obj.GetAnnotations()[CacheBypassAnnotation] == "true" {
+		return r.uncachedReader
+	}
+	return r.dynamicCache
+}
+
 // Builds an object as specified in a phase.
 // Includes system labels, namespace and owner reference.
 func (r *PhaseReconciler) desiredObject(
@@ -238,6 +511,28 @@ func (r *PhaseReconciler) desiredObject(
 	return desiredObj, nil
 }
 
+// Describes an object applied from the given phase object and its probe result.
+// The hash covers the serialized phase object, it changes when the object is re-serialized
+// and can't be used to detect drift of the live object.
+func newManagedObject(
+	phaseObject corev1alpha1.ObjectSetObject, obj *unstructured.Unstructured,
+	available bool, message string,
+) corev1alpha1.ObjectSetManagedObject {
+	gvk := obj.GroupVersionKind()
+	return corev1alpha1.ObjectSetManagedObject{
+		ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+			Group:     gvk.Group,
+			Kind:      gvk.Kind,
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+		Version:   gvk.Version,
+		Hash:      fmt.Sprintf("%x", sha256.Sum256(phaseObject.Object.Raw)),
+		Available: available,
+		Message:   message,
+	}
+}
+
 type CommonObjectPhaseError struct {
 	OwnerKey, ObjectKey client.ObjectKey
 	OwnerGVK, ObjectGVK schema.GroupVersionKind
@@ -270,7 +565,7 @@ func (r *PhaseReconciler) reconcileObject(
 ) (actualObj *unstructured.Unstructured, err error) {
 	objKey := client.ObjectKeyFromObject(desiredObj)
 	currentObj := desiredObj.DeepCopy()
-	err = r.dynamicCache.Get(ctx, objKey, currentObj)
+	err = r.readerFor(desiredObj).Get(ctx, objKey, currentObj)
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, fmt.Errorf("getting %s: %w", desiredObj.GroupVersionKind(), err)
 	}
@@ -292,6 +587,11 @@ func (r *PhaseReconciler) reconcileObject(
 
 	// Check if we can even work on this object or need to adopt it.
 	needsAdoption, err := r.adoptionChecker.Check(ctx, owner, currentObj, previous)
+	if IsCollisionError(err) {
+		if recordErr := r.recordConflict(ctx, owner, currentObj); recordErr != nil {
+			return nil, recordErr
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -313,9 +613,21 @@ func (r *PhaseReconciler) reconcileObject(
 
 	// Only issue updates when this instance is already or will be controlled by this instance.
 	if r.ownerStrategy.IsController(owner.ClientObject(), updatedObj) {
-		if err := r.patcher.Patch(ctx, desiredObj, currentObj, updatedObj); err != nil {
+		patchNeeded, err := r.patcher.Patch(ctx, desiredObj, currentObj, updatedObj)
+		// Objects of a revision are immutable, so changes needed to objects
+		// that we already controlled have to come from someone else.
+		drifted := patchNeeded && !needsAdoption
+		if drifted {
+			r.metricsRecorder.RecordObjectDriftDetected(
+				owner.ClientObject(), desiredObj.GroupVersionKind())
+		}
+		if err != nil {
 			return nil, err
 		}
+		if drifted {
+			r.metricsRecorder.RecordObjectDriftReverted(
+				owner.ClientObject(), desiredObj.GroupVersionKind())
+		}
 	}
 
 	return updatedObj, nil
@@ -331,7 +643,7 @@ func (p *defaultPatcher) Patch(
 	currentObj, // object as currently present on the cluster
 	// deepCopy of currentObj, already updated for owner handling
 	updatedObj *unstructured.Unstructured,
-) error {
+) (patchNeeded bool, err error) {
 	// Ensure desired labels and annotations are present
 	updatedObj.SetLabels(mergeKeysFrom(updatedObj.GetLabels(), desiredObj.GetLabels()))
 	updatedObj.SetAnnotations(mergeKeysFrom(updatedObj.GetAnnotations(), desiredObj.GetAnnotations()))
@@ -349,6 +661,7 @@ func (p *defaultPatcher) Patch(
 
 	// DeepEqual check to prevent unnecessary PATCH calls to the API.
 	if !reflect.DeepEqual(updatedObjMeta, currentObjMeta) {
+		patchNeeded = true
 		// Patch with optimisticLocking to make sure ResourceVersion is checked.
 		// OptimisticLocking is enabled by providing the resourceVersion property in the patch.
 		// Just overriding would risk loosing labels and annotations added by other participants of the system.
@@ -357,38 +670,80 @@ func (p *defaultPatcher) Patch(
 			"metadata": updatedObjMeta,
 		})
 		if err != nil {
-			return fmt.Errorf("creating metadata patch: %w", err)
+			return patchNeeded, fmt.Errorf("creating metadata patch: %w", err)
 		}
 
 		if err := p.writer.Patch(ctx, updatedObj, client.RawPatch(
 			types.MergePatchType, metadataPatch)); err != nil {
-			return fmt.Errorf("patching object metadata: %w", err)
+			return patchNeeded, fmt.Errorf("patching object metadata: %w", err)
+		}
+	}
+
+	// Check for if an update is even needed.
+	if patch, objectPatchNeeded := objectPatch(desiredObj, updatedObj); objectPatchNeeded {
+		patchNeeded = true
+		objectPatchJSON, err := json.Marshal(patch)
+		if err != nil {
+			return patchNeeded, fmt.Errorf("creating metadata patch: %w", err)
+		}
+		if err := p.writer.Patch(ctx, updatedObj, client.RawPatch(
+			types.MergePatchType, objectPatchJSON)); err != nil {
+			return patchNeeded, fmt.Errorf("patching object: %w", err)
 		}
 	}
+	return patchNeeded, nil
+}
 
-	patch := desiredObj.DeepCopy()
+// Returns the fields of desiredObj to patch currentObj with
+// and whether currentObj differs from them.
+func objectPatch(
+	desiredObj, currentObj *unstructured.Unstructured,
+) (patch *unstructured.Unstructured, patchNeeded bool) {
+	patch = desiredObj.DeepCopy()
 	// metadata is already up-to-date and we don't want to patch it without optimistic locking.
 	unstructured.RemoveNestedField(patch.Object, "metadata")
 	// never patch status, even if specified
 	// we would just start a fight with whatever controller is realizing this object.
 	unstructured.RemoveNestedField(patch.Object, "status")
 
-	base := updatedObj.DeepCopy()
+	base := currentObj.DeepCopy()
 	unstructured.RemoveNestedField(base.Object, "metadata")
 	unstructured.RemoveNestedField(base.Object, "status")
 
-	// Check for if an update is even needed.
-	if !equality.Semantic.DeepDerivative(patch, base) {
-		objectPatch, err := json.Marshal(patch)
-		if err != nil {
-			return fmt.Errorf("creating metadata patch: %w", err)
-		}
-		if err := p.writer.Patch(ctx, updatedObj, client.RawPatch(
-			types.MergePatchType, objectPatch)); err != nil {
-			return fmt.Errorf("patching object: %w", err)
-		}
+	return patch, !equality.Semantic.DeepDerivative(patch, base)
+}
+
+// Returns the weight of an object within an ordered phase, defaulting to 0.
+func objectWeight(obj *unstructured.Unstructured) (int, error) {
+	weight, ok := obj.GetAnnotations()[WeightAnnotation]
+	if !ok {
+		return 0, nil
 	}
-	return nil
+	w, err := strconv.Atoi(weight)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation on %s %s: %w",
+			WeightAnnotation, obj.GetKind(), obj.GetName(), err)
+	}
+	return w, nil
+}
+
+// Returns a copy of objects sorted by the given weights,
+// keeping the order of objects with equal weight.
+func sortByWeight(
+	objects []corev1alpha1.ObjectSetObject, weights []int,
+) []corev1alpha1.ObjectSetObject {
+	indexes := make([]int, len(objects))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return weights[indexes[i]] < weights[indexes[j]]
+	})
+	sorted := make([]corev1alpha1.ObjectSetObject, len(objects))
+	for i, idx := range indexes {
+		sorted[i] = objects[idx]
+	}
+	return sorted
 }
 
 func unstructuredFromObjectSetObject(
@@ -404,6 +759,16 @@ func unstructuredFromObjectSetObject(
 	return obj, nil
 }
 
+// Returns true, if all key-value pairs of required are present in base.
+func containsKeys(base, required map[string]string) bool {
+	for k, v := range required {
+		if bv, ok := base[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
 func mergeKeysFrom(base, additional map[string]string) map[string]string {
 	if base == nil {
 		base = map[string]string{}
@@ -431,6 +796,15 @@ func (c *defaultAdoptionChecker) Check(
 		return false, nil
 	}
 
+	adoptable, err := c.isAdoptable(owner, obj)
+	if err != nil {
+		return false, err
+	}
+	if adoptable {
+		// pre-existing object selected for adoption.
+		return true, nil
+	}
+
 	currentRevision, err := getObjectRevision(obj)
 	if err != nil {
 		return false, fmt.Errorf("getting revision of object: %w", err)
@@ -470,6 +844,23 @@ func (c *defaultAdoptionChecker) Check(
 	return true, nil
 }
 
+// Returns true, if the object is not controlled by anyone
+// and matches the adoption selector of the owner.
+func (c *defaultAdoptionChecker) isAdoptable(
+	owner PhaseObjectOwner, obj client.Object,
+) (bool, error) {
+	adoptOwner, ok := owner.(adoptObjectsOwner)
+	if !ok || adoptOwner.GetAdoptObjectsSelector() == nil ||
+		c.ownerStrategy.HasController(obj) {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(adoptOwner.GetAdoptObjectsSelector())
+	if err != nil {
+		return false, fmt.Errorf("parsing adoption selector: %w", err)
+	}
+	return selector.Matches(labels.Set(obj.GetLabels())), nil
+}
+
 func (c *defaultAdoptionChecker) isControlledByPreviousRevision(
 	obj client.Object, previous []client.Object,
 ) bool {
@@ -505,3 +896,58 @@ func setObjectRevision(obj client.Object, revision int64) {
 	a[revisionAnnotation] = fmt.Sprintf("%d", revision)
 	obj.SetAnnotations(a)
 }
+
+// Refuses to delete CustomResourceDefinitions while instances of them exist,
+// because deleting the CRD would cascade to all its instances.
+func (r *PhaseReconciler) checkCRDDeletion(
+	ctx context.Context, desiredObj, currentObj *unstructured.Unstructured,
+) error {
+	if currentObj.GroupVersionKind().GroupKind() != crdGroupKind ||
+		desiredObj.GetAnnotations()[AllowCRDDeletionAnnotation] == "true" {
+		return nil
+	}
+
+	group, _, _ := unstructured.NestedString(currentObj.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(currentObj.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(currentObj.Object, "spec", "versions")
+	var version string
+	for _, v := range versions {
+		v, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if len(version) == 0 || v["storage"] == true {
+			version, _ = v["name"].(string)
+		}
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group: group, Version: version, Kind: kind + "List",
+	})
+	err := r.uncachedReader.List(ctx, list, client.Limit(1))
+	if meta.IsNoMatchError(err) || errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("listing instances of CustomResourceDefinition %s: %w", currentObj.GetName(), err)
+	}
+	if len(list.Items) > 0 {
+		return CRDInstancesExistError{CRD: currentObj.GetName()}
+	}
+	return nil
+}
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// CRDInstancesExistError is returned when teardown would delete a CustomResourceDefinition,
+// that still has instances.
+type CRDInstancesExistError struct {
+	CRD string
+}
+
+func (e CRDInstancesExistError) Error() string {
+	return fmt.Sprintf(
+		"refusing to delete CustomResourceDefinition %s, instances still exist; annotate with %s: \"true\" to override",
+		e.CRD, AllowCRDDeletionAnnotation)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,6 +18,7 @@ import (
 
 	"package-operator.run/apis/core/v1alpha1"
 	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/probing"
 	"package-operator.run/package-operator/internal/testutil"
 )
 
@@ -171,6 +173,129 @@ func TestPhaseReconciler_TeardownPhase(t *testing.T) {
 		ownerStrategy.AssertCalled(t, "IsController", ownerObj, currentObj)
 	})
 
+	t.Run("keep policy", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		dynamicCache := &dynamicCacheMock{}
+		ownerStrategy := &ownerStrategyMock{}
+		r := &PhaseReconciler{
+			writer:        testClient,
+			dynamicCache:  dynamicCache,
+			ownerStrategy: ownerStrategy,
+		}
+
+		owner := &teardownRecorderOwnerMock{}
+		ownerObj := &unstructured.Unstructured{}
+		owner.On("ClientObject").Return(ownerObj)
+		owner.On("GetStatusRevision").Return(int64(5))
+
+		ownerStrategy.
+			On("SetControllerReference", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		dynamicCache.
+			On("Watch", mock.Anything, ownerObj, mock.Anything).
+			Return(nil)
+		currentObj := &unstructured.Unstructured{}
+		dynamicCache.
+			On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				out := args.Get(2).(*unstructured.Unstructured)
+				*out = *currentObj
+			}).
+			Return(nil)
+
+		ownerStrategy.
+			On("IsController", ownerObj, currentObj).
+			Return(true)
+		ownerStrategy.
+			On("RemoveOwner", ownerObj, currentObj)
+		testClient.
+			On("Update", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		done, err := r.TeardownPhase(ctx, owner, corev1alpha1.ObjectSetTemplatePhase{
+			Objects: []corev1alpha1.ObjectSetObject{
+				{
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"v1","kind":"PersistentVolumeClaim",` +
+							`"metadata":{"name":"data","annotations":{"package-operator.run/teardown-policy":"Keep"}}}`),
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, done)
+
+		testClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		ownerStrategy.AssertCalled(t, "RemoveOwner", ownerObj, currentObj)
+		assert.Len(t, owner.orphaned, 1)
+	})
+
+	t.Run("stuck in deletion", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		dynamicCache := &dynamicCacheMock{}
+		ownerStrategy := &ownerStrategyMock{}
+		r := &PhaseReconciler{
+			writer:                testClient,
+			dynamicCache:          dynamicCache,
+			ownerStrategy:         ownerStrategy,
+			forceRemoveFinalizers: true,
+		}
+
+		owner := &teardownRecorderOwnerMock{}
+		ownerObj := &unstructured.Unstructured{}
+		owner.On("ClientObject").Return(ownerObj)
+		owner.On("GetStatusRevision").Return(int64(5))
+
+		ownerStrategy.
+			On("SetControllerReference", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		dynamicCache.
+			On("Watch", mock.Anything, ownerObj, mock.Anything).
+			Return(nil)
+		currentObj := &unstructured.Unstructured{}
+		deletionTimestamp := metav1.NewTime(time.Now().Add(-time.Hour))
+		currentObj.SetDeletionTimestamp(&deletionTimestamp)
+		currentObj.SetFinalizers([]string{"package-operator.run/cached", "example.com/cleanup"})
+		dynamicCache.
+			On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				out := args.Get(2).(*unstructured.Unstructured)
+				*out = *currentObj.DeepCopy()
+			}).
+			Return(nil)
+
+		ownerStrategy.
+			On("IsController", ownerObj, mock.Anything).
+			Return(true)
+		testClient.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+		var updated *unstructured.Unstructured
+		testClient.
+			On("Update", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				updated = args.Get(1).(*unstructured.Unstructured)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		done, err := r.TeardownPhase(ctx, owner, corev1alpha1.ObjectSetTemplatePhase{
+			Objects: []corev1alpha1.ObjectSetObject{
+				{
+					Object: runtime.RawExtension{},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Len(t, owner.stuck, 1)
+		require.NotNil(t, updated)
+		assert.Equal(t, []string{"example.com/cleanup"}, updated.GetFinalizers())
+	})
+
 	t.Run("not controller", func(t *testing.T) {
 		dynamicCache := &dynamicCacheMock{}
 		ownerStrategy := &ownerStrategyMock{}
@@ -230,6 +355,241 @@ func TestPhaseReconciler_TeardownPhase(t *testing.T) {
 		ownerStrategy.AssertCalled(t, "RemoveOwner", ownerObj, currentObj)
 		testClient.AssertCalled(t, "Update", mock.Anything, currentObj, mock.Anything)
 	})
+	t.Run("CRD after its instances", func(t *testing.T) {
+		testClient := testutil.NewClient()
+		dynamicCache := &dynamicCacheMock{}
+		ownerStrategy := &ownerStrategyMock{}
+		r := &PhaseReconciler{
+			writer:         testClient,
+			uncachedReader: testClient,
+			dynamicCache:   dynamicCache,
+			ownerStrategy:  ownerStrategy,
+		}
+
+		owner := &phaseObjectOwnerMock{}
+		ownerObj := &unstructured.Unstructured{}
+		owner.On("ClientObject").Return(ownerObj)
+		owner.On("GetStatusRevision").Return(int64(5))
+
+		ownerStrategy.
+			On("SetControllerReference", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+		ownerStrategy.
+			On("IsController", ownerObj, mock.Anything).
+			Return(true)
+		dynamicCache.
+			On("Watch", mock.Anything, ownerObj, mock.Anything).
+			Return(nil)
+
+		isKind := func(kind string) interface{} {
+			return mock.MatchedBy(func(obj client.Object) bool {
+				return obj.GetObjectKind().GroupVersionKind().Kind == kind
+			})
+		}
+		// The instance is gone after the first teardown.
+		dynamicCache.
+			On("Get", mock.Anything, mock.Anything, isKind("Example")).
+			Return(nil).
+			Once()
+		dynamicCache.
+			On("Get", mock.Anything, mock.Anything, isKind("Example")).
+			Return(errors.NewNotFound(schema.GroupResource{}, ""))
+		dynamicCache.
+			On("Get", mock.Anything, mock.Anything, isKind("CustomResourceDefinition")).
+			Return(nil)
+		testClient.
+			On("List", mock.Anything, mock.AnythingOfType("*unstructured.UnstructuredList"), mock.Anything).
+			Return(nil)
+		var deleted []string
+		testClient.
+			On("Delete", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				deleted = append(deleted, args.Get(1).(client.Object).GetObjectKind().GroupVersionKind().Kind)
+			}).
+			Return(nil)
+
+		// The CRD is listed before its instance.
+		phase := corev1alpha1.ObjectSetTemplatePhase{
+			Objects: []corev1alpha1.ObjectSetObject{
+				{
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition",` +
+							`"metadata":{"name":"examples.test.package-operator.run"},` +
+							`"spec":{"group":"test.package-operator.run","names":{"kind":"Example"},` +
+							`"versions":[{"name":"v1","storage":true}]}}`),
+					},
+				},
+				{
+					Object: runtime.RawExtension{
+						Raw: []byte(`{"apiVersion":"test.package-operator.run/v1","kind":"Example",` +
+							`"metadata":{"name":"test"}}`),
+					},
+				},
+			},
+		}
+
+		ctx := context.Background()
+		done, err := r.TeardownPhase(ctx, owner, phase)
+		require.NoError(t, err)
+		assert.False(t, done)
+		// The CRD is left alone, while its instance is still being deleted.
+		assert.Equal(t, []string{"Example"}, deleted)
+		testClient.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+
+		done, err = r.TeardownPhase(ctx, owner, phase)
+		require.NoError(t, err)
+		assert.False(t, done) // wait for delete confirm
+		assert.Equal(t, []string{"Example", "CustomResourceDefinition"}, deleted)
+	})
+}
+
+func TestPhaseReconciler_DetectPhaseDrift(t *testing.T) {
+	dynamicCache := &dynamicCacheMock{}
+	ownerStrategy := &ownerStrategyMock{}
+	r := &PhaseReconciler{
+		dynamicCache:  dynamicCache,
+		ownerStrategy: ownerStrategy,
+	}
+	owner := &phaseObjectOwnerMock{}
+	ownerObj := &unstructured.Unstructured{}
+	owner.On("ClientObject").Return(ownerObj)
+	owner.On("GetStatusRevision").Return(int64(1))
+
+	ownerStrategy.
+		On("SetControllerReference", mock.Anything, mock.Anything).
+		Return(nil)
+	ownerStrategy.
+		On("IsController", ownerObj, mock.MatchedBy(func(obj metav1.Object) bool {
+			return obj.GetName() == "foreign"
+		})).
+		Return(false)
+	ownerStrategy.
+		On("IsController", ownerObj, mock.Anything).
+		Return(true)
+
+	dynamicCache.
+		On("Get", mock.Anything, client.ObjectKey{Name: "missing", Namespace: "test"}, mock.Anything).
+		Return(errors.NewNotFound(schema.GroupResource{}, ""))
+	dynamicCache.
+		On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*unstructured.Unstructured)
+			if obj.GetName() == "drifted" {
+				obj.Object["data"] = map[string]interface{}{"key": "changed"}
+			}
+		}).
+		Return(nil)
+
+	newObject := func(name string) corev1alpha1.ObjectSetObject {
+		return corev1alpha1.ObjectSetObject{Object: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name +
+				`","namespace":"test"},"data":{"key":"value"}}`),
+		}}
+	}
+
+	ctx := context.Background()
+	drifted, err := r.DetectPhaseDrift(ctx, owner, corev1alpha1.ObjectSetTemplatePhase{
+		Objects: []corev1alpha1.ObjectSetObject{
+			newObject("ok"), newObject("drifted"), newObject("missing"), newObject("foreign"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"ConfigMap test/drifted",
+		"ConfigMap test/missing (missing)",
+		"ConfigMap test/foreign (not controlled)",
+	}, drifted)
+}
+
+func TestPhaseReconciler_ReconcilePhase_kindNotAllowed(t *testing.T) {
+	testClient := testutil.NewClient()
+	dynamicCacheMock := &dynamicCacheMock{}
+	r := &PhaseReconciler{
+		writer:       testClient,
+		dynamicCache: dynamicCacheMock,
+		kindPolicy: KindPolicy{
+			Denied: GroupKinds{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: {}},
+		},
+	}
+	owner := &phaseObjectOwnerMock{}
+
+	ctx := context.Background()
+	_, err := r.ReconcilePhase(ctx, owner, corev1alpha1.ObjectSetTemplatePhase{
+		Objects: []corev1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`),
+			}},
+			{Object: runtime.RawExtension{
+				Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding","metadata":{"name":"crb"}}`),
+			}},
+		},
+	}, nil, nil)
+	assert.ErrorIs(t, err, KindNotAllowedError{
+		GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	})
+
+	// Nothing has been applied.
+	dynamicCacheMock.AssertNotCalled(t, "Watch", mock.Anything, mock.Anything, mock.Anything)
+	testClient.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPhaseReconciler_ReconcilePhase_controlledObjects(t *testing.T) {
+	dynamicCache := &dynamicCacheMock{}
+	ownerStrategy := &ownerStrategyMock{}
+	r := &PhaseReconciler{
+		dynamicCache:  dynamicCache,
+		ownerStrategy: ownerStrategy,
+	}
+	owner := &controlledObjectsRecorderOwnerMock{}
+	ownerObj := &unstructured.Unstructured{}
+	owner.On("ClientObject").Return(ownerObj)
+	owner.On("GetStatusRevision").Return(int64(1))
+	owner.On("IsPaused").Return(true)
+
+	ownerStrategy.
+		On("SetControllerReference", mock.Anything, mock.Anything).
+		Return(nil)
+	ownerStrategy.
+		On("IsController", ownerObj, mock.MatchedBy(func(obj metav1.Object) bool {
+			return obj.GetName() == "foreign"
+		})).
+		Return(false)
+	ownerStrategy.
+		On("IsController", ownerObj, mock.Anything).
+		Return(true)
+	dynamicCache.
+		On("Watch", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	dynamicCache.
+		On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	newObject := func(name string) corev1alpha1.ObjectSetObject {
+		return corev1alpha1.ObjectSetObject{Object: runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name +
+				`","namespace":"test"}}`),
+		}}
+	}
+
+	ctx := context.Background()
+	_, err := r.ReconcilePhase(ctx, owner, corev1alpha1.ObjectSetTemplatePhase{
+		Objects: []corev1alpha1.ObjectSetObject{
+			newObject("controlled"), newObject("foreign"),
+		},
+	}, probing.ParseProbes(ctx, nil), nil)
+	require.NoError(t, err)
+	if assert.Len(t, owner.controlled, 1) {
+		assert.Equal(t, "controlled", owner.controlled[0].GetName())
+	}
+	if assert.Len(t, owner.managed, 1) {
+		managed := owner.managed[0]
+		assert.Equal(t, corev1alpha1.ObjectSetObjectReference{
+			Kind: "ConfigMap", Name: "controlled", Namespace: "test",
+		}, managed.ObjectSetObjectReference)
+		assert.Equal(t, "v1", managed.Version)
+		assert.Len(t, managed.Hash, 64)
+		assert.True(t, managed.Available)
+	}
 }
 
 func TestPhaseReconciler_reconcileObject_create(t *testing.T) {
@@ -256,6 +616,68 @@ func TestPhaseReconciler_reconcileObject_create(t *testing.T) {
 	assert.Same(t, desired, actual)
 }
 
+func TestPhaseReconciler_readerFor(t *testing.T) {
+	dynamicCache := &dynamicCacheMock{}
+	uncachedReader := testutil.NewClient()
+	r := &PhaseReconciler{
+		dynamicCache:   dynamicCache,
+		uncachedReader: uncachedReader,
+	}
+
+	obj := &unstructured.Unstructured{}
+	assert.Same(t, dynamicCache, r.readerFor(obj))
+
+	obj.SetAnnotations(map[string]string{CacheBypassAnnotation: "true"})
+	assert.Same(t, uncachedReader, r.readerFor(obj))
+}
+
+func TestPhaseReconciler_reconcileObject_drift(t *testing.T) {
+	testClient := testutil.NewClient()
+	dynamicCacheMock := &dynamicCacheMock{}
+	acMock := &adoptionCheckerMock{}
+	ownerStrategy := &ownerStrategyMock{}
+	patcher := &patcherMock{}
+	recorder := &metricsRecorderMock{}
+	r := &PhaseReconciler{
+		writer:          testClient,
+		dynamicCache:    dynamicCacheMock,
+		adoptionChecker: acMock,
+		ownerStrategy:   ownerStrategy,
+		patcher:         patcher,
+		metricsRecorder: recorder,
+	}
+	ownerObj := &unstructured.Unstructured{}
+	owner := &phaseObjectOwnerMock{}
+	owner.On("ClientObject").Return(ownerObj)
+
+	// already controlled by owner
+	acMock.
+		On("Check", mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+	dynamicCacheMock.
+		On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+	ownerStrategy.
+		On("IsController", mock.Anything, mock.Anything).
+		Return(true)
+	patcher.
+		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(true, nil)
+
+	desiredObj := &unstructured.Unstructured{}
+	desiredObj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group: "apps", Version: "v1", Kind: "Deployment",
+	})
+	recorder.On("RecordObjectDriftDetected", ownerObj, desiredObj.GroupVersionKind())
+	recorder.On("RecordObjectDriftReverted", ownerObj, desiredObj.GroupVersionKind())
+
+	ctx := context.Background()
+	_, err := r.reconcileObject(ctx, owner, desiredObj, nil)
+	require.NoError(t, err)
+
+	recorder.AssertExpectations(t)
+}
+
 func TestPhaseReconciler_reconcileObject_update(t *testing.T) {
 	testClient := testutil.NewClient()
 	dynamicCacheMock := &dynamicCacheMock{}
@@ -291,7 +713,7 @@ func TestPhaseReconciler_reconcileObject_update(t *testing.T) {
 
 	patcher.
 		On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
-		Return(nil)
+		Return(true, nil)
 
 	ctx := context.Background()
 	actual, err := r.reconcileObject(ctx, owner, &unstructured.Unstructured{}, nil)
@@ -514,6 +936,40 @@ func Test_defaultAdoptionChecker_Check(t *testing.T) {
 	}
 }
 
+func Test_defaultAdoptionChecker_Check_adoptObjects(t *testing.T) {
+	osm := &ownerStrategyMock{}
+	c := &defaultAdoptionChecker{ownerStrategy: osm}
+	ownerObj := &unstructured.Unstructured{}
+	owner := &adoptObjectsOwnerMock{
+		selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "example"},
+		},
+	}
+	owner.On("ClientObject").Return(ownerObj)
+	owner.On("GetStatusRevision").Return(int64(1))
+
+	matching := &unstructured.Unstructured{}
+	matching.SetLabels(map[string]string{"app": "example"})
+	other := &unstructured.Unstructured{}
+	other.SetLabels(map[string]string{"app": "other"})
+	controlled := matching.DeepCopy()
+	controlled.SetName("controlled")
+
+	osm.On("IsController", mock.Anything, mock.Anything).Return(false)
+	osm.On("HasController", controlled).Return(true)
+	osm.On("HasController", mock.Anything).Return(false)
+
+	needsAdoption, err := c.Check(context.Background(), owner, matching, nil)
+	require.NoError(t, err)
+	assert.True(t, needsAdoption)
+
+	_, err = c.Check(context.Background(), owner, other, nil)
+	assert.ErrorAs(t, err, &ObjectNotOwnedByPreviousRevisionError{})
+
+	_, err = c.Check(context.Background(), owner, controlled, nil)
+	assert.ErrorAs(t, err, &ObjectNotOwnedByPreviousRevisionError{})
+}
+
 func Test_defaultPatcher_patchObject_update_metadata(t *testing.T) {
 	clientMock := testutil.NewClient()
 	r := &defaultPatcher{
@@ -551,8 +1007,9 @@ func Test_defaultPatcher_patchObject_update_metadata(t *testing.T) {
 	}
 	updatedObj := currentObj.DeepCopy()
 
-	err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
+	patchNeeded, err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
 	require.NoError(t, err)
+	assert.True(t, patchNeeded)
 
 	clientMock.AssertNumberOfCalls(t, "Patch", 1) // only a single PATCH request
 	if len(patches) == 1 {
@@ -608,8 +1065,9 @@ func Test_defaultPatcher_patchObject_update_no_metadata(t *testing.T) {
 	}
 	updatedObj := currentObj.DeepCopy()
 
-	err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
+	patchNeeded, err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
 	require.NoError(t, err)
+	assert.True(t, patchNeeded)
 
 	clientMock.AssertNumberOfCalls(t, "Patch", 1) // only a single PATCH request
 	if len(patches) == 1 {
@@ -637,8 +1095,9 @@ func Test_defaultPatcher_patchObject_noop(t *testing.T) {
 	currentObj := &unstructured.Unstructured{}
 	updatedObj := &unstructured.Unstructured{}
 
-	err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
+	patchNeeded, err := r.Patch(ctx, desiredObj, currentObj, updatedObj)
 	require.NoError(t, err)
+	assert.False(t, patchNeeded)
 
 	clientMock.AssertNotCalled(
 		t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
@@ -687,6 +1146,110 @@ func Test_mergeKeysFrom(t *testing.T) {
 	}
 }
 
+func TestPhaseReconciler_checkCRDDeletion(t *testing.T) {
+	crd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata": map[string]interface{}{
+				"name": "examples.test.package-operator.run",
+			},
+			"spec": map[string]interface{}{
+				"group": "test.package-operator.run",
+				"names": map[string]interface{}{
+					"kind": "Example",
+				},
+				"versions": []interface{}{
+					map[string]interface{}{"name": "v1alpha1"},
+					map[string]interface{}{"name": "v1", "storage": true},
+				},
+			},
+		},
+	}
+
+	t.Run("instances exist", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &PhaseReconciler{uncachedReader: c}
+		c.
+			On("List", mock.Anything, mock.AnythingOfType("*unstructured.UnstructuredList"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*unstructured.UnstructuredList)
+				assert.Equal(t, schema.GroupVersionKind{
+					Group: "test.package-operator.run", Version: "v1", Kind: "ExampleList",
+				}, list.GroupVersionKind())
+				list.Items = []unstructured.Unstructured{{}}
+			}).
+			Return(nil)
+
+		err := r.checkCRDDeletion(context.Background(), crd, crd)
+		var crdErr CRDInstancesExistError
+		require.ErrorAs(t, err, &crdErr)
+		assert.Equal(t, "examples.test.package-operator.run", crdErr.CRD)
+	})
+
+	t.Run("no instances", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &PhaseReconciler{uncachedReader: c}
+		c.
+			On("List", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil)
+
+		err := r.checkCRDDeletion(context.Background(), crd, crd)
+		require.NoError(t, err)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &PhaseReconciler{uncachedReader: c}
+		desired := crd.DeepCopy()
+		desired.SetAnnotations(map[string]string{AllowCRDDeletionAnnotation: "true"})
+
+		err := r.checkCRDDeletion(context.Background(), desired, crd)
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_sortByWeight(t *testing.T) {
+	newObject := func(name, weight string) corev1alpha1.ObjectSetObject {
+		annotations := ""
+		if len(weight) > 0 {
+			annotations = `,"annotations":{"package-operator.run/weight":"` + weight + `"}`
+		}
+		return corev1alpha1.ObjectSetObject{
+			Object: runtime.RawExtension{
+				Raw: []byte(`{"kind":"ConfigMap","metadata":{"name":"` + name + `"` + annotations + `}}`),
+			},
+		}
+	}
+	objects := []corev1alpha1.ObjectSetObject{
+		newObject("c", "10"),
+		newObject("a", ""),
+		newObject("b", "-5"),
+		newObject("d", "10"),
+	}
+
+	weights := make([]int, len(objects))
+	for i := range objects {
+		obj, err := unstructuredFromObjectSetObject(&objects[i])
+		require.NoError(t, err)
+		weights[i], err = objectWeight(obj)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int{10, 0, -5, 10}, weights)
+
+	sorted := sortByWeight(objects, weights)
+	assert.Equal(t, []corev1alpha1.ObjectSetObject{
+		objects[2], objects[1], objects[0], objects[3],
+	}, sorted)
+
+	invalidObject := newObject("x", "first")
+	invalid, err := unstructuredFromObjectSetObject(&invalidObject)
+	require.NoError(t, err)
+	_, err = objectWeight(invalid)
+	assert.Error(t, err)
+}
+
 type ownerStrategyMock struct {
 	mock.Mock
 }
@@ -696,6 +1259,11 @@ func (m *ownerStrategyMock) IsController(owner, obj metav1.Object) bool {
 	return args.Bool(0)
 }
 
+func (m *ownerStrategyMock) HasController(obj metav1.Object) bool {
+	args := m.Called(obj)
+	return args.Bool(0)
+}
+
 func (m *ownerStrategyMock) RemoveOwner(owner, obj metav1.Object) {
 	m.Called(owner, obj)
 }
@@ -728,6 +1296,45 @@ func (m *phaseObjectOwnerMock) IsPaused() bool {
 	return args.Bool(0)
 }
 
+type adoptObjectsOwnerMock struct {
+	phaseObjectOwnerMock
+	selector *metav1.LabelSelector
+}
+
+func (m *adoptObjectsOwnerMock) GetAdoptObjectsSelector() *metav1.LabelSelector {
+	return m.selector
+}
+
+type teardownRecorderOwnerMock struct {
+	phaseObjectOwnerMock
+	orphaned []client.Object
+	stuck    []client.Object
+}
+
+func (m *teardownRecorderOwnerMock) RecordOrphanedObject(obj client.Object) {
+	m.orphaned = append(m.orphaned, obj)
+}
+
+func (m *teardownRecorderOwnerMock) RecordStuckObject(obj client.Object) {
+	m.stuck = append(m.stuck, obj)
+}
+
+type controlledObjectsRecorderOwnerMock struct {
+	phaseObjectOwnerMock
+	controlled []client.Object
+	managed    []corev1alpha1.ObjectSetManagedObject
+}
+
+func (m *controlledObjectsRecorderOwnerMock) RecordControlledObject(obj client.Object) {
+	m.controlled = append(m.controlled, obj)
+}
+
+func (m *controlledObjectsRecorderOwnerMock) RecordManagedObject(
+	managed corev1alpha1.ObjectSetManagedObject,
+) {
+	m.managed = append(m.managed, managed)
+}
+
 type dynamicCacheMock struct {
 	testutil.CtrlClient
 }
@@ -757,7 +1364,29 @@ type patcherMock struct {
 func (m *patcherMock) Patch(
 	ctx context.Context,
 	desiredObj, currentObj, updatedObj *unstructured.Unstructured,
-) error {
+) (bool, error) {
 	args := m.Called(ctx, desiredObj, currentObj, updatedObj)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
+}
+
+type metricsRecorderMock struct {
+	mock.Mock
+}
+
+func (m *metricsRecorderMock) RecordObjectDriftDetected(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	m.Called(owner, gvk)
+}
+
+func (m *metricsRecorderMock) RecordObjectDriftReverted(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	m.Called(owner, gvk)
+}
+
+func (m *metricsRecorderMock) RecordObjectConflict(
+	owner client.Object, gvk schema.GroupVersionKind, manager string,
+) {
+	m.Called(owner, gvk, manager)
 }
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RateLimitedClient waits for the rate limiter before every write,
+// so rollouts of ObjectSets with many objects don't overwhelm small API servers.
+// Reads are mostly served from caches and are not limited.
+type RateLimitedClient struct {
+	client.Client
+	limiter flowcontrol.RateLimiter
+}
+
+// Creates a new RateLimitedClient.
+// The limiter may be shared to cap the writes of multiple clients together.
+func NewRateLimitedClient(
+	c client.Client, limiter flowcontrol.RateLimiter,
+) *RateLimitedClient {
+	return &RateLimitedClient{
+		Client:  c,
+		limiter: limiter,
+	}
+}
+
+func (c *RateLimitedClient) Create(
+	ctx context.Context, obj client.Object, opts ...client.CreateOption,
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *RateLimitedClient) Delete(
+	ctx context.Context, obj client.Object, opts ...client.DeleteOption,
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *RateLimitedClient) Update(
+	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *RateLimitedClient) Patch(
+	ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption,
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *RateLimitedClient) DeleteAllOf(
+	ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption,
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
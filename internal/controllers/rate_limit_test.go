@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestRateLimitedClient(t *testing.T) {
+	wrapped := testutil.NewClient()
+	c := NewRateLimitedClient(wrapped, flowcontrol.NewTokenBucketRateLimiter(1, 1))
+
+	wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, c.Create(context.Background(), &corev1.ConfigMap{}))
+	wrapped.AssertNumberOfCalls(t, "Create", 1)
+
+	// Writes waiting for the limiter are aborted with their context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, c.Create(ctx, &corev1.ConfigMap{}))
+	wrapped.AssertNumberOfCalls(t, "Create", 1)
+}
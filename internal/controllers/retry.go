@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// RetryPolicy configures retries of writes failing with transient errors.
+type RetryPolicy struct {
+	// Number of retries after the first attempt.
+	Retries int
+	// Time to wait before the first retry, doubled for every further retry.
+	Backoff time.Duration
+}
+
+func (p RetryPolicy) String() string {
+	return fmt.Sprintf("%dx%s", p.Retries, p.Backoff)
+}
+
+// GroupKindRetryPolicies maps GroupKinds to retry policies.
+// Implements flag.Value, parsing a list like "ValidatingWebhookConfiguration.admissionregistration.k8s.io=3x2s",
+// retrying up to 3 times, 2s after the first failure.
+type GroupKindRetryPolicies map[schema.GroupKind]RetryPolicy
+
+func (p GroupKindRetryPolicies) String() string {
+	pairs := make([]string, 0, len(p))
+	for gk, policy := range p {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", gk, policy))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p GroupKindRetryPolicies) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid retry policy %q, expected Kind.group=RETRIESxBACKOFF", pair)
+		}
+		policyParts := strings.SplitN(strings.TrimSpace(parts[1]), "x", 2)
+		if len(policyParts) != 2 {
+			return fmt.Errorf("invalid retry policy %q, expected Kind.group=RETRIESxBACKOFF", pair)
+		}
+		retries, err := strconv.Atoi(policyParts[0])
+		if err != nil || retries < 1 {
+			return fmt.Errorf("invalid retry policy %q, expected a positive number of retries", pair)
+		}
+		backoff, err := time.ParseDuration(policyParts[1])
+		if err != nil || backoff <= 0 {
+			return fmt.Errorf("invalid retry policy %q, expected a positive backoff", pair)
+		}
+		p[schema.ParseGroupKind(strings.TrimSpace(parts[0]))] = RetryPolicy{
+			Retries: retries,
+			Backoff: backoff,
+		}
+	}
+	return nil
+}
+
+// RetryingClient retries writes failing with transient errors, according to the policy of the object's kind.
+// Webhooks may refuse connections shortly after being registered
+// and CustomResourceDefinitions may not be served right after creation.
+// Writes of kinds without a policy fail right away and are retried by requeuing the reconcile.
+type RetryingClient struct {
+	client.Client
+	policies GroupKindRetryPolicies
+	// waits before retrying, replaced in tests.
+	after func(d time.Duration) <-chan time.Time
+}
+
+func NewRetryingClient(
+	c client.Client, policies GroupKindRetryPolicies,
+) *RetryingClient {
+	return &RetryingClient{
+		Client:   c,
+		policies: policies,
+		after:    time.After,
+	}
+}
+
+func (c *RetryingClient) retry(
+	ctx context.Context, obj client.Object, write func() error,
+) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return write()
+	}
+	policy, ok := c.policies[gvk.GroupKind()]
+	if !ok {
+		return write()
+	}
+
+	backoff := policy.Backoff
+	for attempt := 0; ; attempt++ {
+		err := write()
+		if err == nil || attempt >= policy.Retries || !isTransientError(err) {
+			return err
+		}
+		select {
+		case <-c.after(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// Errors that may go away without changing the request.
+func isTransientError(err error) bool {
+	return apierrors.IsInternalError(err) || // e.g. webhook not reachable
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		meta.IsNoMatchError(err) // CustomResourceDefinition not served yet
+}
+
+func (c *RetryingClient) Create(
+	ctx context.Context, obj client.Object, opts ...client.CreateOption,
+) error {
+	return c.retry(ctx, obj, func() error {
+		return c.Client.Create(ctx, obj, opts...)
+	})
+}
+
+func (c *RetryingClient) Delete(
+	ctx context.Context, obj client.Object, opts ...client.DeleteOption,
+) error {
+	return c.retry(ctx, obj, func() error {
+		return c.Client.Delete(ctx, obj, opts...)
+	})
+}
+
+func (c *RetryingClient) Update(
+	ctx context.Context, obj client.Object, opts ...client.UpdateOption,
+) error {
+	return c.retry(ctx, obj, func() error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *RetryingClient) Patch(
+	ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption,
+) error {
+	return c.retry(ctx, obj, func() error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *RetryingClient) DeleteAllOf(
+	ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption,
+) error {
+	return c.retry(ctx, obj, func() error {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	})
+}
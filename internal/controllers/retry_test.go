@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestGroupKindRetryPolicies(t *testing.T) {
+	p := GroupKindRetryPolicies{}
+	require.NoError(t, p.Set("ValidatingWebhookConfiguration.admissionregistration.k8s.io=3x2s, Secret=1x100ms"))
+
+	assert.Equal(t, GroupKindRetryPolicies{
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}: {
+			Retries: 3, Backoff: 2 * time.Second,
+		},
+		{Kind: "Secret"}: {Retries: 1, Backoff: 100 * time.Millisecond},
+	}, p)
+	assert.Equal(t,
+		"Secret=1x100ms,ValidatingWebhookConfiguration.admissionregistration.k8s.io=3x2s", p.String())
+
+	assert.Error(t, p.Set("Secret=3"))
+	assert.Error(t, p.Set("Secret=0x1s"))
+	assert.Error(t, p.Set("Secret=3xsoon"))
+}
+
+func TestRetryingClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	transientErr := apierrors.NewInternalError(assert.AnError)
+
+	newClient := func() (*RetryingClient, *testutil.CtrlClient, *[]time.Duration) {
+		wrapped := testutil.NewClient()
+		wrapped.On("Scheme").Return(scheme)
+		c := NewRetryingClient(wrapped, GroupKindRetryPolicies{
+			{Kind: "ConfigMap"}: {Retries: 2, Backoff: time.Second},
+		})
+		var waited []time.Duration
+		c.after = func(d time.Duration) <-chan time.Time {
+			waited = append(waited, d)
+			ch := make(chan time.Time, 1)
+			ch <- time.Now()
+			return ch
+		}
+		return c, wrapped, &waited
+	}
+
+	t.Run("retries transient errors with backoff", func(t *testing.T) {
+		c, wrapped, waited := newClient()
+		wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(transientErr).Twice()
+		wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, c.Create(context.Background(), &corev1.ConfigMap{}))
+		wrapped.AssertNumberOfCalls(t, "Create", 3)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *waited)
+	})
+
+	t.Run("gives up after all retries", func(t *testing.T) {
+		c, wrapped, _ := newClient()
+		wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(transientErr)
+
+		assert.ErrorIs(t, c.Create(context.Background(), &corev1.ConfigMap{}), transientErr)
+		wrapped.AssertNumberOfCalls(t, "Create", 3)
+	})
+
+	t.Run("doesn't retry other errors", func(t *testing.T) {
+		c, wrapped, _ := newClient()
+		wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Return(apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, "test"))
+
+		assert.Error(t, c.Create(context.Background(), &corev1.ConfigMap{}))
+		wrapped.AssertNumberOfCalls(t, "Create", 1)
+	})
+
+	t.Run("doesn't retry kinds without policy", func(t *testing.T) {
+		c, wrapped, _ := newClient()
+		wrapped.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(transientErr)
+
+		assert.Error(t, c.Create(context.Background(), &corev1.Secret{}))
+		wrapped.AssertNumberOfCalls(t, "Create", 1)
+	})
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GroupKindDurations maps GroupKinds to durations.
+// Implements flag.Value, parsing a list like "CustomResourceDefinition.apiextensions.k8s.io=5s".
+type GroupKindDurations map[schema.GroupKind]time.Duration
+
+func (d GroupKindDurations) String() string {
+	pairs := make([]string, 0, len(d))
+	for gk, duration := range d {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", gk, duration))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (d GroupKindDurations) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if len(strings.TrimSpace(pair)) == 0 {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid duration %q, expected Kind.group=duration", pair)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || duration < 0 {
+			return fmt.Errorf("invalid duration %q, expected a positive duration", pair)
+		}
+		d[schema.ParseGroupKind(strings.TrimSpace(parts[0]))] = duration
+	}
+	return nil
+}
+
+// Returns an ObjectSettlingError, if the object was created less than its settle delay ago.
+// Objects of kinds like CustomResourceDefinitions or webhook configurations
+// may need time to take effect, before dependent objects can be reconciled.
+func (r *PhaseReconciler) checkSettled(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	delay, ok := r.settleDelays[gvk.GroupKind()]
+	if !ok {
+		return nil
+	}
+	remaining := delay - time.Since(obj.GetCreationTimestamp().Time)
+	if remaining <= 0 {
+		return nil
+	}
+	return ObjectSettlingError{
+		ObjectGVK:    gvk,
+		ObjectKey:    client.ObjectKeyFromObject(obj),
+		RequeueAfter: remaining,
+	}
+}
+
+// ObjectSettlingError is returned when an object was created too recently
+// for later objects to be reconciled.
+type ObjectSettlingError struct {
+	ObjectGVK    schema.GroupVersionKind
+	ObjectKey    client.ObjectKey
+	RequeueAfter time.Duration
+}
+
+func (e ObjectSettlingError) Error() string {
+	return fmt.Sprintf("waiting %s for %s %s to settle after creation",
+		e.RequeueAfter.Round(time.Second), e.ObjectGVK.Kind, e.ObjectKey)
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGroupKindDurations(t *testing.T) {
+	d := GroupKindDurations{}
+	require.NoError(t, d.Set("CustomResourceDefinition.apiextensions.k8s.io=5s, Secret=1m"))
+
+	assert.Equal(t, GroupKindDurations{
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: 5 * time.Second,
+		{Kind: "Secret"}: time.Minute,
+	}, d)
+	assert.Equal(t, "CustomResourceDefinition.apiextensions.k8s.io=5s,Secret=1m0s", d.String())
+
+	assert.Error(t, d.Set("Secret"))
+	assert.Error(t, d.Set("Secret=soon"))
+}
+
+func TestPhaseReconciler_checkSettled(t *testing.T) {
+	r := &PhaseReconciler{
+		settleDelays: GroupKindDurations{
+			{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: time.Minute,
+		},
+	}
+	newObject := func(kind string, age time.Duration) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("apiextensions.k8s.io/v1")
+		obj.SetKind(kind)
+		obj.SetName("test")
+		obj.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-age)))
+		return obj
+	}
+
+	assert.NoError(t, r.checkSettled(newObject("CustomResourceDefinition", 2*time.Minute)))
+	assert.NoError(t, r.checkSettled(newObject("Other", 0)))
+
+	err := r.checkSettled(newObject("CustomResourceDefinition", 0))
+	var settlingErr ObjectSettlingError
+	require.ErrorAs(t, err, &settlingErr)
+	assert.Equal(t, schema.GroupVersionKind{
+		Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition",
+	}, settlingErr.ObjectGVK)
+	assert.InDelta(t, time.Minute, settlingErr.RequeueAfter, float64(5*time.Second))
+}
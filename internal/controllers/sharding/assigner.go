@@ -0,0 +1,198 @@
+package sharding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+)
+
+// Delay before checking again whether the controlling object or previous revision has been assigned a shard.
+const waitForOwnerShardDelay = 5 * time.Second
+
+// ShardAssigner sets the shard label on objects that are not assigned to a valid shard.
+//
+// Objects that need to be reconciled together are kept on the same shard:
+// - namespaced objects are assigned by namespace
+// - ObjectSets inherit the shard of their previous revisions
+// - ObjectSetPhases inherit the shard of their controlling ObjectSet.
+type ShardAssigner struct {
+	client client.Client
+	log    logr.Logger
+	scheme *runtime.Scheme
+	gvk    schema.GroupVersionKind
+	shards int
+}
+
+func NewShardAssigner(
+	c client.Client, log logr.Logger,
+	scheme *runtime.Scheme, gvk schema.GroupVersionKind,
+	shards int,
+) *ShardAssigner {
+	return &ShardAssigner{
+		client: c,
+		log:    log,
+		scheme: scheme,
+		gvk:    gvk,
+		shards: shards,
+	}
+}
+
+func (a *ShardAssigner) SetupWithManager(mgr ctrl.Manager) error {
+	obj, err := a.newObject(a.gvk)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(a.gvk.Kind+"ShardAssigner").
+		For(obj, builder.WithPredicates(
+			predicate.NewPredicateFuncs(a.needsAssignment),
+		)).
+		Complete(a)
+}
+
+func (a *ShardAssigner) Reconcile(
+	ctx context.Context, req ctrl.Request,
+) (ctrl.Result, error) {
+	log := a.log.WithValues(a.gvk.Kind, req.String())
+
+	obj, err := a.newObject(a.gvk)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := a.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !a.needsAssignment(obj) {
+		return ctrl.Result{}, nil
+	}
+
+	shard, ok, err := a.shardFor(ctx, obj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ok {
+		return ctrl.Result{RequeueAfter: waitForOwnerShardDelay}, nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{
+				controllers.ShardLabel: strconv.Itoa(shard),
+			},
+		},
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("marshalling patch to assign shard: %w", err)
+	}
+	if err := a.client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patchJSON)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("assigning shard: %w", err)
+	}
+	log.Info("assigned shard", "shard", shard)
+	return ctrl.Result{}, nil
+}
+
+func (a *ShardAssigner) needsAssignment(obj client.Object) bool {
+	_, ok := ShardOf(obj, a.shards)
+	return !ok
+}
+
+// Determines the shard for the given object.
+// ok is false, if the shard of the controlling object has not been assigned yet.
+func (a *ShardAssigner) shardFor(
+	ctx context.Context, obj client.Object,
+) (shard int, ok bool, err error) {
+	switch o := obj.(type) {
+	case *corev1alpha1.ObjectSetPhase, *corev1alpha1.ClusterObjectSetPhase:
+		if controllerRef := metav1.GetControllerOf(obj); controllerRef != nil {
+			return a.shardOfController(ctx, obj, controllerRef)
+		}
+
+	case *corev1alpha1.ObjectSet:
+		return a.shardOfPrevious(ctx, obj, o.Spec.Previous)
+
+	case *corev1alpha1.ClusterObjectSet:
+		return a.shardOfPrevious(ctx, obj, o.Spec.Previous)
+	}
+
+	return a.shardOfKey(obj), true, nil
+}
+
+func (a *ShardAssigner) shardOfKey(obj client.Object) int {
+	key := obj.GetNamespace()
+	if len(key) == 0 {
+		key = obj.GetName()
+	}
+	return ShardForKey(key, a.shards)
+}
+
+func (a *ShardAssigner) shardOfController(
+	ctx context.Context, obj client.Object, controllerRef *metav1.OwnerReference,
+) (shard int, ok bool, err error) {
+	owner, err := a.newObject(schema.FromAPIVersionAndKind(
+		controllerRef.APIVersion, controllerRef.Kind))
+	if err != nil {
+		return 0, false, err
+	}
+	key := client.ObjectKey{Name: controllerRef.Name, Namespace: obj.GetNamespace()}
+	if err := a.client.Get(ctx, key, owner); err != nil {
+		return 0, false, fmt.Errorf("getting controller: %w", err)
+	}
+	shard, ok = ShardOf(owner, a.shards)
+	return shard, ok, nil
+}
+
+// Returns the shard of the first previous revision that is assigned to a shard.
+// ok is false, if previous revisions exist, but none of them has been assigned a shard yet.
+// Objects without any existing previous revision are assigned by key.
+func (a *ShardAssigner) shardOfPrevious(
+	ctx context.Context, obj client.Object,
+	previous []corev1alpha1.PreviousRevisionReference,
+) (shard int, ok bool, err error) {
+	var waitForPrevious bool
+	for _, prev := range previous {
+		prevObj, err := a.newObject(a.gvk)
+		if err != nil {
+			return 0, false, err
+		}
+		key := client.ObjectKey{Name: prev.Name, Namespace: obj.GetNamespace()}
+		if err := a.client.Get(ctx, key, prevObj); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return 0, false, fmt.Errorf("getting previous revision: %w", err)
+		}
+		if shard, ok := ShardOf(prevObj, a.shards); ok {
+			return shard, true, nil
+		}
+		waitForPrevious = true
+	}
+	if waitForPrevious {
+		return 0, false, nil
+	}
+	return a.shardOfKey(obj), true, nil
+}
+
+func (a *ShardAssigner) newObject(gvk schema.GroupVersionKind) (client.Object, error) {
+	obj, err := a.scheme.New(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("creating object for %s: %w", gvk, err)
+	}
+	return obj.(client.Object), nil
+}
@@ -0,0 +1,156 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/controllers"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+var testScheme = runtime.NewScheme()
+
+func init() {
+	if err := corev1alpha1.AddToScheme(testScheme); err != nil {
+		panic(err)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	obj := &corev1alpha1.ObjectSet{}
+	_, ok := ShardOf(obj, 3)
+	assert.False(t, ok, "not assigned")
+
+	obj.Labels = map[string]string{controllers.ShardLabel: "5"}
+	_, ok = ShardOf(obj, 3)
+	assert.False(t, ok, "out of range")
+
+	obj.Labels = map[string]string{controllers.ShardLabel: "2"}
+	shard, ok := ShardOf(obj, 3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, shard)
+}
+
+func TestShardAssigner_shardFor(t *testing.T) {
+	t.Run("namespaced by namespace", func(t *testing.T) {
+		c := testutil.NewClient()
+		a := NewShardAssigner(c, ctrl.Log, testScheme,
+			corev1alpha1.GroupVersion.WithKind("ObjectSet"), 3)
+
+		obj := &corev1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-1", Namespace: "test"},
+		}
+		shard, ok, err := a.shardFor(context.Background(), obj)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, ShardForKey("test", 3), shard)
+	})
+
+	t.Run("inherits from previous revision", func(t *testing.T) {
+		c := testutil.NewClient()
+		a := NewShardAssigner(c, ctrl.Log, testScheme,
+			corev1alpha1.GroupVersion.WithKind("ClusterObjectSet"), 3)
+
+		c.
+			On("Get", mock.Anything, client.ObjectKey{Name: "test-1"}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*corev1alpha1.ClusterObjectSet)
+				obj.Labels = map[string]string{controllers.ShardLabel: "1"}
+			}).
+			Return(nil)
+
+		obj := &corev1alpha1.ClusterObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-2"},
+			Spec: corev1alpha1.ClusterObjectSetSpec{
+				Previous: []corev1alpha1.PreviousRevisionReference{{Name: "test-1"}},
+			},
+		}
+		shard, ok, err := a.shardFor(context.Background(), obj)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 1, shard)
+	})
+
+	t.Run("waits for previous revision", func(t *testing.T) {
+		c := testutil.NewClient()
+		a := NewShardAssigner(c, ctrl.Log, testScheme,
+			corev1alpha1.GroupVersion.WithKind("ClusterObjectSet"), 3)
+
+		c.
+			On("Get", mock.Anything, client.ObjectKey{Name: "test-1"}, mock.Anything).
+			Return(nil)
+
+		obj := &corev1alpha1.ClusterObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-2"},
+			Spec: corev1alpha1.ClusterObjectSetSpec{
+				Previous: []corev1alpha1.PreviousRevisionReference{{Name: "test-1"}},
+			},
+		}
+		_, ok, err := a.shardFor(context.Background(), obj)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("previous revision gone", func(t *testing.T) {
+		c := testutil.NewClient()
+		a := NewShardAssigner(c, ctrl.Log, testScheme,
+			corev1alpha1.GroupVersion.WithKind("ClusterObjectSet"), 3)
+
+		c.
+			On("Get", mock.Anything, client.ObjectKey{Name: "test-1"}, mock.Anything).
+			Return(apierrors.NewNotFound(schema.GroupResource{}, "test-1"))
+
+		obj := &corev1alpha1.ClusterObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-2"},
+			Spec: corev1alpha1.ClusterObjectSetSpec{
+				Previous: []corev1alpha1.PreviousRevisionReference{{Name: "test-1"}},
+			},
+		}
+		shard, ok, err := a.shardFor(context.Background(), obj)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, ShardForKey("test-2", 3), shard)
+	})
+
+	t.Run("waits for controller", func(t *testing.T) {
+		c := testutil.NewClient()
+		a := NewShardAssigner(c, ctrl.Log, testScheme,
+			corev1alpha1.GroupVersion.WithKind("ClusterObjectSetPhase"), 3)
+
+		c.
+			On("Get", mock.Anything, client.ObjectKey{Name: "test"}, mock.Anything).
+			Return(nil)
+
+		obj := &corev1alpha1.ClusterObjectSetPhase{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-phase",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: corev1alpha1.GroupVersion.String(),
+						Kind:       "ClusterObjectSet",
+						Name:       "test",
+						Controller: boolPtr(true),
+					},
+				},
+			},
+		}
+		_, ok, err := a.shardFor(context.Background(), obj)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
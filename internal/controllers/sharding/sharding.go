@@ -0,0 +1,39 @@
+package sharding
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"package-operator.run/package-operator/internal/controllers"
+)
+
+// Returns the shard for the given key.
+func ShardForKey(key string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// Returns the shard the given object is assigned to.
+// ok is false, if the object is not assigned or its shard is out of range.
+func ShardOf(obj client.Object, shards int) (shard int, ok bool) {
+	value, exists := obj.GetLabels()[controllers.ShardLabel]
+	if !exists {
+		return 0, false
+	}
+	shard, err := strconv.Atoi(value)
+	if err != nil || shard < 0 || shard >= shards {
+		return 0, false
+	}
+	return shard, true
+}
+
+// Returns a label selector matching all objects assigned to the given shard.
+func Selector(shard int) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		controllers.ShardLabel: strconv.Itoa(shard),
+	})
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Default minimum time between status writes of the same object.
+const DefaultStatusFlushInterval = 2 * time.Second
+
+// StatusUpdateBatcher coalesces rapid successive status updates of the same object.
+// After a status write, further writes within the flush interval are deferred
+// and the object is requeued after a jittered delay,
+// so all changes accumulated until then are persisted in a single write.
+type StatusUpdateBatcher struct {
+	interval time.Duration
+	now      func() time.Time
+	jitter   func(max time.Duration) time.Duration
+
+	mux       sync.Mutex
+	lastWrite map[types.UID]time.Time
+}
+
+func NewStatusUpdateBatcher(interval time.Duration) *StatusUpdateBatcher {
+	return &StatusUpdateBatcher{
+		interval: interval,
+		now:      time.Now,
+		jitter: func(max time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(max) + 1)) //nolint:gosec
+		},
+		lastWrite: map[types.UID]time.Time{},
+	}
+}
+
+// Returns how long the status write of the given object should be deferred.
+// Zero means the status should be written right away.
+func (b *StatusUpdateBatcher) Delay(obj client.Object) time.Duration {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	last, ok := b.lastWrite[obj.GetUID()]
+	if !ok {
+		return 0
+	}
+	elapsed := b.now().Sub(last)
+	if elapsed >= b.interval {
+		return 0
+	}
+	// Jitter spreads flushes of objects that changed at the same time.
+	return b.interval - elapsed + b.jitter(b.interval/5)
+}
+
+// Records that the status of the given object has been written.
+func (b *StatusUpdateBatcher) Written(obj client.Object) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	now := b.now()
+	// Forget writes that no longer defer anything, so deleted objects don't pile up.
+	for uid, last := range b.lastWrite {
+		if now.Sub(last) >= b.interval {
+			delete(b.lastWrite, uid)
+		}
+	}
+	b.lastWrite[obj.GetUID()] = now
+}
+
+// Returns true, if the .status of updated differs from original.
+func StatusChanged(original, updated client.Object) bool {
+	originalMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+	if err != nil {
+		return true
+	}
+	updatedMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(updated)
+	if err != nil {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(originalMap["status"], updatedMap["status"])
+}
+
+// Returns a JSON merge patch, turning the .status of original into the .status of the patched object.
+// In contrast to client.Merge, fields and map keys that have been removed from .status
+// are explicitly set to null, so they are cleared on the server, even when tagged omitempty.
+// Only .status is included, so the patch is safe to send without optimistic locking.
+func StatusPatch(original client.Object) client.Patch {
+	return statusPatch{original: original}
+}
+
+type statusPatch struct {
+	original client.Object
+}
+
+func (p statusPatch) Type() types.PatchType {
+	return types.MergePatchType
+}
+
+func (p statusPatch) Data(obj client.Object) ([]byte, error) {
+	originalJSON, err := statusJSON(p.original)
+	if err != nil {
+		return nil, err
+	}
+	updatedJSON, err := statusJSON(obj)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(originalJSON, updatedJSON)
+}
+
+func statusJSON(obj client.Object) ([]byte, error) {
+	objMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{"status": objMap["status"]})
+}
+
+// Merges two reconcile results, requeuing at the earliest requested time.
+func MergeResults(a, b ctrl.Result) ctrl.Result {
+	res := ctrl.Result{Requeue: a.Requeue || b.Requeue}
+	switch {
+	case a.RequeueAfter == 0:
+		res.RequeueAfter = b.RequeueAfter
+	case b.RequeueAfter == 0 || a.RequeueAfter < b.RequeueAfter:
+		res.RequeueAfter = a.RequeueAfter
+	default:
+		res.RequeueAfter = b.RequeueAfter
+	}
+	return res
+}
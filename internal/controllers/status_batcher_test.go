@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func TestStatusUpdateBatcher(t *testing.T) {
+	now := time.Now()
+	b := NewStatusUpdateBatcher(2 * time.Second)
+	b.now = func() time.Time { return now }
+	b.jitter = func(time.Duration) time.Duration { return 0 }
+
+	obj := &corev1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{UID: "1"}}
+	other := &corev1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{UID: "2"}}
+
+	assert.Zero(t, b.Delay(obj), "never written")
+
+	b.Written(obj)
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, 1500*time.Millisecond, b.Delay(obj))
+	assert.Zero(t, b.Delay(other))
+
+	now = now.Add(2 * time.Second)
+	assert.Zero(t, b.Delay(obj), "interval passed")
+
+	b.Written(other)
+	assert.NotContains(t, b.lastWrite, obj.UID, "outdated entry pruned")
+}
+
+func TestStatusChanged(t *testing.T) {
+	original := &corev1alpha1.ObjectSet{}
+	updated := original.DeepCopy()
+	updated.Labels = map[string]string{"test": "test"}
+	assert.False(t, StatusChanged(original, updated))
+
+	updated.Status.Phase = corev1alpha1.ObjectSetStatusPhaseAvailable
+	assert.True(t, StatusChanged(original, updated))
+}
+
+func TestStatusPatch(t *testing.T) {
+	original := &corev1alpha1.ObjectSet{
+		Status: corev1alpha1.ObjectSetStatus{
+			Phase: corev1alpha1.ObjectSetStatusPhaseNotReady,
+			Conflicts: []corev1alpha1.ObjectSetConflict{
+				{
+					ObjectSetObjectReference: corev1alpha1.ObjectSetObjectReference{
+						Kind: "ConfigMap", Name: "test", Namespace: "test",
+					},
+					Controller: "Deployment/test",
+				},
+			},
+		},
+	}
+	updated := original.DeepCopy()
+	updated.Labels = map[string]string{"test": "test"}
+	updated.Status.Phase = corev1alpha1.ObjectSetStatusPhaseAvailable
+	updated.Status.Conflicts = nil
+
+	patch := StatusPatch(original)
+	data, err := patch.Data(updated)
+	require.NoError(t, err)
+	assert.Equal(t, types.MergePatchType, patch.Type())
+	assert.JSONEq(t, `{"status":{"phase":"Available","conflicts":null}}`, string(data))
+}
+
+func TestStatusPatch_mapKeys(t *testing.T) {
+	original := &corev1alpha1.ObjectSet{
+		Status: corev1alpha1.ObjectSetStatus{
+			CollectedStatus: map[string]string{"version": "v1", "replicas": "3"},
+		},
+	}
+	updated := original.DeepCopy()
+	updated.Status.CollectedStatus = map[string]string{"version": "v2"}
+
+	data, err := StatusPatch(original).Data(updated)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":{"collectedStatus":{"version":"v2","replicas":null}}}`, string(data))
+
+	updated.Status.CollectedStatus = nil
+	data, err = StatusPatch(original).Data(updated)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":{"collectedStatus":null}}`, string(data))
+}
+
+func TestMergeResults(t *testing.T) {
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second},
+		MergeResults(ctrl.Result{}, ctrl.Result{RequeueAfter: time.Second}))
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second},
+		MergeResults(ctrl.Result{RequeueAfter: time.Second}, ctrl.Result{RequeueAfter: time.Minute}))
+	assert.Equal(t, ctrl.Result{Requeue: true, RequeueAfter: time.Second},
+		MergeResults(ctrl.Result{RequeueAfter: time.Minute}, ctrl.Result{Requeue: true, RequeueAfter: time.Second}))
+}
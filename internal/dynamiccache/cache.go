@@ -3,6 +3,7 @@ package dynamiccache
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"sync"
 
@@ -30,13 +31,14 @@ type OwnerReference struct {
 type informerMap interface {
 	Get(
 		ctx context.Context,
-		gvk schema.GroupVersionKind,
+		key informerKey,
 		obj runtime.Object,
 	) (informer cache.SharedIndexInformer, reader client.Reader, err error)
 	Delete(
 		ctx context.Context,
-		gvk schema.GroupVersionKind,
+		key informerKey,
 	) error
+	HasSynced() bool
 }
 
 type cacheSourcer interface {
@@ -52,11 +54,12 @@ var (
 
 type Cache struct {
 	scheme      *runtime.Scheme
+	mapper      restMapper
 	opts        CacheOptions
 	informerMap informerMap
 
 	informerReferencesMux sync.RWMutex
-	informerReferences    map[schema.GroupVersionKind]map[OwnerReference]struct{}
+	informerReferences    map[informerKey]map[OwnerReference]struct{}
 
 	cacheSource cacheSourcer
 }
@@ -69,7 +72,8 @@ func NewCache(
 ) *Cache {
 	c := &Cache{
 		scheme:             scheme,
-		informerReferences: map[schema.GroupVersionKind]map[OwnerReference]struct{}{},
+		mapper:             mapper,
+		informerReferences: map[informerKey]map[OwnerReference]struct{}{},
 		cacheSource:        &cacheSource{},
 	}
 	for _, opt := range opts {
@@ -79,7 +83,8 @@ func NewCache(
 
 	c.informerMap = NewInformerMap(
 		config, scheme, mapper,
-		c.opts.ResyncInterval, c.opts.Selectors, c.opts.Indexers)
+		c.opts.ResyncInterval, c.opts.Selectors, c.opts.Indexers,
+		c.opts.StripFields)
 
 	return c
 }
@@ -106,16 +111,23 @@ func (c *Cache) OwnersForGKV(gvk schema.GroupVersionKind) []OwnerReference {
 	c.informerReferencesMux.RLock()
 	defer c.informerReferencesMux.RUnlock()
 
-	refs, ok := c.informerReferences[gvk]
-	if !ok {
+	// Owners may be spread across multiple namespace-scoped informers.
+	owners := map[OwnerReference]struct{}{}
+	for key, refs := range c.informerReferences {
+		if key.GroupVersionKind != gvk {
+			continue
+		}
+		for ownerRef := range refs {
+			owners[ownerRef] = struct{}{}
+		}
+	}
+	if len(owners) == 0 {
 		return nil
 	}
 
-	ownerRefs := make([]OwnerReference, len(refs))
-	var i int
-	for ownerRef := range refs {
-		ownerRefs[i] = ownerRef
-		i++
+	ownerRefs := make([]OwnerReference, 0, len(owners))
+	for ownerRef := range owners {
+		ownerRefs = append(ownerRefs, ownerRef)
 	}
 	return ownerRefs
 }
@@ -137,22 +149,27 @@ func (c *Cache) Watch(
 	if err != nil {
 		return err
 	}
+	key, err := c.informerKeyForOwner(gvk, owner)
+	if err != nil {
+		return err
+	}
 
 	// Remember Owner watching this GVK
-	_, informerExists := c.informerReferences[gvk]
+	_, informerExists := c.informerReferences[key]
 	if !informerExists {
-		c.informerReferences[gvk] = map[OwnerReference]struct{}{}
+		c.informerReferences[key] = map[OwnerReference]struct{}{}
 	}
-	c.informerReferences[gvk][ownerRef] = struct{}{}
+	c.informerReferences[key][ownerRef] = struct{}{}
 
 	if !informerExists {
 		log.Info("adding new watcher",
 			"ownerGV", ownerRef.GroupKind,
 			"forGVK", gvk.String(),
-			"ownerNamespace", owner.GetNamespace())
+			"ownerNamespace", owner.GetNamespace(),
+			"informerNamespace", key.Namespace)
 
 		// Create/Get Informer
-		informer, _, err := c.informerMap.Get(ctx, gvk, obj)
+		informer, _, err := c.informerMap.Get(ctx, key, obj)
 		if err != nil {
 			return fmt.Errorf("getting informer from InformerMap: %w", err)
 		}
@@ -180,26 +197,59 @@ func (c *Cache) Free(
 		return err
 	}
 
-	for gvk, refs := range c.informerReferences {
+	for key, refs := range c.informerReferences {
 		if _, ok := refs[ownerRef]; ok {
 			delete(refs, ownerRef)
 
 			if len(refs) == 0 {
 				log.Info("releasing watcher",
-					"kind", gvk.Kind, "group", gvk.Group,
-					"ownerNamespace", owner.GetNamespace())
+					"kind", key.Kind, "group", key.Group,
+					"ownerNamespace", owner.GetNamespace(),
+					"informerNamespace", key.Namespace)
 
-				if err := c.informerMap.Delete(ctx, gvk); err != nil {
-					return fmt.Errorf("releasing informer for %v: %w", gvk, err)
+				if err := c.informerMap.Delete(ctx, key); err != nil {
+					return fmt.Errorf("releasing informer for %v: %w", key.GroupVersionKind, err)
 				}
 
-				delete(c.informerReferences, gvk)
+				delete(c.informerReferences, key)
 			}
 		}
 	}
 	return nil
 }
 
+// Stop shuts down all informers of the cache,
+// e.g. when the cluster it is watching is no longer used.
+func (c *Cache) Stop(ctx context.Context) error {
+	c.informerReferencesMux.Lock()
+	defer c.informerReferencesMux.Unlock()
+
+	for key := range c.informerReferences {
+		if err := c.informerMap.Delete(ctx, key); err != nil {
+			return fmt.Errorf("releasing informer for %v: %w", key.GroupVersionKind, err)
+		}
+		delete(c.informerReferences, key)
+	}
+	return nil
+}
+
+// CacheNotSyncedError is returned by the SyncedChecker,
+// while informers are still loading their initial state.
+type CacheNotSyncedError struct{}
+
+func (CacheNotSyncedError) Error() string {
+	return "dynamic cache informers have not synced"
+}
+
+// SyncedChecker returns an error while not all informers have synced.
+// Can be used as healthz.Checker for readiness probes.
+func (c *Cache) SyncedChecker(_ *http.Request) error {
+	if !c.informerMap.HasSynced() {
+		return CacheNotSyncedError{}
+	}
+	return nil
+}
+
 // CacheNotStartedError is returned when trying to read from a cache before starting a watch.
 type CacheNotStartedError struct{}
 
@@ -221,11 +271,12 @@ func (c *Cache) Get(
 	// And that the cache is not deleted while the get call is still in-flight.
 	c.informerReferencesMux.RLock()
 	defer c.informerReferencesMux.RUnlock()
-	if _, ok := c.informerReferences[gvk]; !ok {
+	informerKey, ok := c.informerKeyForRead(gvk, key.Namespace)
+	if !ok {
 		return &CacheNotStartedError{}
 	}
 
-	_, reader, err := c.informerMap.Get(ctx, gvk, out)
+	_, reader, err := c.informerMap.Get(ctx, informerKey, out)
 	if err != nil {
 		return fmt.Errorf("getting Informer from Map: %w", err)
 	}
@@ -248,16 +299,87 @@ func (c *Cache) List(
 	// And that the cache is not deleted while the list call is still in-flight.
 	c.informerReferencesMux.RLock()
 	defer c.informerReferencesMux.RUnlock()
-	if _, ok := c.informerReferences[gvk]; !ok {
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if key, ok := c.informerKeyForRead(gvk, listOpts.Namespace); ok {
+		_, reader, err := c.informerMap.Get(ctx, key, out)
+		if err != nil {
+			return fmt.Errorf("getting Informer from Map: %w", err)
+		}
+		return reader.List(ctx, out, opts...)
+	}
+	if len(listOpts.Namespace) > 0 {
 		return &CacheNotStartedError{}
 	}
 
-	_, reader, err := c.informerMap.Get(ctx, gvk, out)
+	// List across all namespace-scoped informers of this GVK.
+	var (
+		items []runtime.Object
+		found bool
+	)
+	for key := range c.informerReferences {
+		if key.GroupVersionKind != gvk {
+			continue
+		}
+		found = true
+
+		_, reader, err := c.informerMap.Get(ctx, key, out)
+		if err != nil {
+			return fmt.Errorf("getting Informer from Map: %w", err)
+		}
+		list := out.DeepCopyObject().(client.ObjectList)
+		if err := reader.List(ctx, list, opts...); err != nil {
+			return err
+		}
+		listItems, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		items = append(items, listItems...)
+	}
+	if !found {
+		return &CacheNotStartedError{}
+	}
+	return meta.SetList(out, items)
+}
+
+// Returns the key of the informer to register the owners watch with.
+// With NamespacedWatches, namespaced objects are watched in the namespace of their owner.
+func (c *Cache) informerKeyForOwner(
+	gvk schema.GroupVersionKind, owner client.Object,
+) (informerKey, error) {
+	key := informerKey{GroupVersionKind: gvk}
+	if !c.opts.NamespacedWatches || len(owner.GetNamespace()) == 0 {
+		return key, nil
+	}
+
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
-		return fmt.Errorf("getting Informer from Map: %w", err)
+		return key, fmt.Errorf("getting REST mapping for %v: %w", gvk, err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		key.Namespace = owner.GetNamespace()
+	}
+	return key, nil
+}
+
+// Returns the key of an existing informer able to serve reads in the given namespace.
+// Namespace-scoped informers are preferred over cluster-wide informers.
+// Must be called while holding informerReferencesMux.
+func (c *Cache) informerKeyForRead(
+	gvk schema.GroupVersionKind, namespace string,
+) (informerKey, bool) {
+	if len(namespace) > 0 {
+		key := informerKey{GroupVersionKind: gvk, Namespace: namespace}
+		if _, ok := c.informerReferences[key]; ok {
+			return key, true
+		}
 	}
 
-	return reader.List(ctx, out, opts...)
+	key := informerKey{GroupVersionKind: gvk}
+	_, ok := c.informerReferences[key]
+	return key, ok
 }
 
 func (c *Cache) ownerRef(owner client.Object) (OwnerReference, error) {
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -59,7 +60,7 @@ func TestCache_OwnersForGVK(t *testing.T) {
 		Version: "v1beta3",
 		Group:   "testing.package-operator.run",
 	}
-	c.informerReferences[gvk] = map[OwnerReference]struct{}{
+	c.informerReferences[informerKey{GroupVersionKind: gvk}] = map[OwnerReference]struct{}{
 		owner1: {},
 		owner2: {},
 	}
@@ -96,19 +97,21 @@ func TestCache_Watch(t *testing.T) {
 		err := c.Watch(ctx, owner, obj)
 		require.NoError(t, err)
 
-		informerMap.AssertCalled(t, "Get", mock.Anything, schema.GroupVersionKind{
-			Kind:    "Secret",
-			Version: "v1",
+		informerMap.AssertCalled(t, "Get", mock.Anything, informerKey{
+			GroupVersionKind: schema.GroupVersionKind{
+				Kind:    "Secret",
+				Version: "v1",
+			},
 		}, obj)
 		cacheSource.AssertCalled(t, "handleNewInformer", mock.Anything)
 	})
 
 	t.Run("informer exists", func(t *testing.T) {
 		c, cacheSource, informerMap := setupTestCache(t)
-		c.informerReferences[schema.GroupVersionKind{
+		c.informerReferences[informerKey{GroupVersionKind: schema.GroupVersionKind{
 			Kind:    "Secret",
 			Version: "v1",
-		}] = map[OwnerReference]struct{}{}
+		}}] = map[OwnerReference]struct{}{}
 
 		informerMap.
 			On("Get", mock.Anything, mock.Anything, mock.Anything).
@@ -134,6 +137,36 @@ func TestCache_Watch(t *testing.T) {
 		informerMap.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
 		cacheSource.AssertNotCalled(t, "handleNewInformer", mock.Anything)
 	})
+
+	t.Run("namespaced watches", func(t *testing.T) {
+		c, cacheSource, informerMap := setupTestCache(t)
+		c.opts.NamespacedWatches = true
+		c.mapper = &restMapperMock{scope: meta.RESTScopeNamespace}
+
+		informerMap.
+			On("Get", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, nil, nil)
+		cacheSource.On("handleNewInformer", mock.Anything).Return(nil)
+
+		ctx := context.Background()
+		owner := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test42",
+				Namespace: "test",
+			},
+		}
+		obj := &corev1.Secret{}
+		err := c.Watch(ctx, owner, obj)
+		require.NoError(t, err)
+
+		informerMap.AssertCalled(t, "Get", mock.Anything, informerKey{
+			GroupVersionKind: schema.GroupVersionKind{
+				Kind:    "Secret",
+				Version: "v1",
+			},
+			Namespace: "test",
+		}, obj)
+	})
 }
 
 func TestCache_Free(t *testing.T) {
@@ -146,10 +179,10 @@ func TestCache_Free(t *testing.T) {
 	}
 	ref, err := c.ownerRef(owner)
 	require.NoError(t, err)
-	c.informerReferences[schema.GroupVersionKind{
+	c.informerReferences[informerKey{GroupVersionKind: schema.GroupVersionKind{
 		Kind:    "Secret",
 		Version: "v1",
-	}] = map[OwnerReference]struct{}{
+	}}] = map[OwnerReference]struct{}{
 		ref: {},
 	}
 	informerMap.
@@ -160,9 +193,44 @@ func TestCache_Free(t *testing.T) {
 	err = c.Free(ctx, owner)
 	require.NoError(t, err)
 
-	informerMap.AssertCalled(t, "Delete", mock.Anything, schema.GroupVersionKind{
-		Kind:    "Secret",
-		Version: "v1",
+	informerMap.AssertCalled(t, "Delete", mock.Anything, informerKey{
+		GroupVersionKind: schema.GroupVersionKind{
+			Kind:    "Secret",
+			Version: "v1",
+		},
+	})
+}
+
+func TestCache_Stop(t *testing.T) {
+	c, _, informerMap := setupTestCache(t)
+	secretKey := informerKey{GroupVersionKind: schema.GroupVersionKind{Kind: "Secret", Version: "v1"}}
+	configMapKey := informerKey{GroupVersionKind: schema.GroupVersionKind{Kind: "ConfigMap", Version: "v1"}}
+	c.informerReferences[secretKey] = map[OwnerReference]struct{}{{Name: "a"}: {}}
+	c.informerReferences[configMapKey] = map[OwnerReference]struct{}{{Name: "b"}: {}}
+	informerMap.
+		On("Delete", mock.Anything, mock.Anything).
+		Return(nil)
+
+	require.NoError(t, c.Stop(context.Background()))
+
+	informerMap.AssertCalled(t, "Delete", mock.Anything, secretKey)
+	informerMap.AssertCalled(t, "Delete", mock.Anything, configMapKey)
+	assert.Empty(t, c.informerReferences)
+}
+
+func TestCache_SyncedChecker(t *testing.T) {
+	t.Run("synced", func(t *testing.T) {
+		c, _, informerMap := setupTestCache(t)
+		informerMap.On("HasSynced").Return(true)
+
+		assert.NoError(t, c.SyncedChecker(nil))
+	})
+
+	t.Run("not synced", func(t *testing.T) {
+		c, _, informerMap := setupTestCache(t)
+		informerMap.On("HasSynced").Return(false)
+
+		assert.ErrorIs(t, c.SyncedChecker(nil), CacheNotSyncedError{})
 	})
 }
 
@@ -176,10 +244,10 @@ func TestCache_Reader(t *testing.T) {
 	}
 	ref, err := c.ownerRef(owner)
 	require.NoError(t, err)
-	c.informerReferences[schema.GroupVersionKind{
+	c.informerReferences[informerKey{GroupVersionKind: schema.GroupVersionKind{
 		Kind:    "Secret",
 		Version: "v1",
-	}] = map[OwnerReference]struct{}{
+	}}] = map[OwnerReference]struct{}{
 		ref: {},
 	}
 
@@ -216,9 +284,53 @@ func TestCache_Reader(t *testing.T) {
 		reader.AssertCalled(t, "List", mock.Anything, obj, mock.Anything)
 	})
 
+	t.Run("List across namespaces", func(t *testing.T) {
+		secretGVK := schema.GroupVersionKind{Kind: "Secret", Version: "v1"}
+		c.informerReferences = map[informerKey]map[OwnerReference]struct{}{
+			{GroupVersionKind: secretGVK, Namespace: "a"}: {ref: {}},
+			{GroupVersionKind: secretGVK, Namespace: "b"}: {ref: {}},
+		}
+
+		nsReader := func(namespace string) *readerMock {
+			r := &readerMock{}
+			r.
+				On("List", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					list := args.Get(1).(*corev1.SecretList)
+					list.Items = []corev1.Secret{
+						{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: namespace}},
+					}
+				}).
+				Return(nil)
+			return r
+		}
+		im := &informerMapMock{}
+		c.informerMap = im
+		im.
+			On("Get", mock.Anything, informerKey{GroupVersionKind: secretGVK, Namespace: "a"}, mock.Anything).
+			Return(nil, nsReader("a"), nil)
+		im.
+			On("Get", mock.Anything, informerKey{GroupVersionKind: secretGVK, Namespace: "b"}, mock.Anything).
+			Return(nil, nsReader("b"), nil)
+
+		obj := &corev1.SecretList{}
+		ctx := context.Background()
+		err = c.List(ctx, obj)
+		require.NoError(t, err)
+		assert.Len(t, obj.Items, 2)
+
+		// Namespaced list only hits the namespaced informer.
+		obj = &corev1.SecretList{}
+		err = c.List(ctx, obj, client.InNamespace("b"))
+		require.NoError(t, err)
+		if assert.Len(t, obj.Items, 1) {
+			assert.Equal(t, "b", obj.Items[0].Namespace)
+		}
+	})
+
 	// "reset" informerReferences to test error case,
 	// when no informer has been registered beforehand.
-	c.informerReferences = map[schema.GroupVersionKind]map[OwnerReference]struct{}{}
+	c.informerReferences = map[informerKey]map[OwnerReference]struct{}{}
 
 	t.Run("Get no informer", func(t *testing.T) {
 		obj := &corev1.Secret{}
@@ -248,7 +360,7 @@ func setupTestCache(t *testing.T) (*Cache, *cacheSourceMock, *informerMapMock) {
 
 	c := &Cache{
 		scheme:             scheme,
-		informerReferences: map[schema.GroupVersionKind]map[OwnerReference]struct{}{},
+		informerReferences: map[informerKey]map[OwnerReference]struct{}{},
 		cacheSource:        cacheSource,
 		informerMap:        informerMap,
 	}
@@ -266,10 +378,10 @@ type informerMapMock struct {
 
 func (m *informerMapMock) Get(
 	ctx context.Context,
-	gvk schema.GroupVersionKind,
+	key informerKey,
 	obj runtime.Object,
 ) (informer cache.SharedIndexInformer, reader client.Reader, err error) {
-	args := m.Called(ctx, gvk, obj)
+	args := m.Called(ctx, key, obj)
 	if i := args.Get(0); i != nil {
 		informer = i.(cache.SharedIndexInformer)
 	}
@@ -282,12 +394,17 @@ func (m *informerMapMock) Get(
 
 func (m *informerMapMock) Delete(
 	ctx context.Context,
-	gvk schema.GroupVersionKind,
+	key informerKey,
 ) error {
-	args := m.Called(ctx, gvk)
+	args := m.Called(ctx, key)
 	return args.Error(0)
 }
 
+func (m *informerMapMock) HasSynced() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
 type cacheSourceMock struct {
 	mock.Mock
 }
@@ -311,6 +428,16 @@ func (m *cacheSourceMock) handleNewInformer(informer cache.SharedIndexInformer)
 	return args.Error(0)
 }
 
+type restMapperMock struct {
+	scope meta.RESTScope
+}
+
+func (m *restMapperMock) RESTMapping(
+	gk schema.GroupKind, versions ...string,
+) (*meta.RESTMapping, error) {
+	return &meta.RESTMapping{Scope: m.scope}, nil
+}
+
 type readerMock struct {
 	mock.Mock
 }
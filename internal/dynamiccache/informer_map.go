@@ -20,6 +20,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// informerKey identifies an informer in the InformerMap.
+type informerKey struct {
+	schema.GroupVersionKind
+	// Namespace the informer is scoped to.
+	// Empty for cluster-wide informers.
+	Namespace string
+}
+
 type mapEntry struct {
 	Informer cache.SharedIndexInformer
 	Reader   client.Reader
@@ -39,16 +47,18 @@ func NewInformerMap(
 	resync time.Duration,
 	selectors SelectorsByGVK,
 	indexers FieldIndexersByGVK,
+	stripFields StripFieldsByGVK,
 ) *InformerMap {
 	return &InformerMap{
-		config:    config,
-		scheme:    scheme,
-		mapper:    mapper,
-		resync:    resync,
-		selectors: selectors.forGVK,
-		indexers:  indexers.forGVK,
-
-		informers:     map[schema.GroupVersionKind]mapEntry{},
+		config:      config,
+		scheme:      scheme,
+		mapper:      mapper,
+		resync:      resync,
+		selectors:   selectors.forGVK,
+		indexers:    indexers.forGVK,
+		stripFields: stripFields.forGVK,
+
+		informers:     map[informerKey]mapEntry{},
 		dynamicClient: dynamic.NewForConfigOrDie(config),
 	}
 }
@@ -76,18 +86,21 @@ type InformerMap struct {
 	// indexers are index functions that create custom field indexes on the cache.
 	indexers func(gvk schema.GroupVersionKind) []FieldIndexer
 
-	informers    map[schema.GroupVersionKind]mapEntry
+	// stripFields configures fields to drop from objects before caching them.
+	stripFields func(gvk schema.GroupVersionKind) StripFields
+
+	informers    map[informerKey]mapEntry
 	informersMux sync.RWMutex
 
 	// dynamicClient to create new ListWatches.
 	dynamicClient dynamic.Interface
 }
 
-// Get returns a informer for the given GVK.
+// Get returns a informer for the given GVK and namespace.
 // If no informer is registered, a new Informer will be created.
 func (im *InformerMap) Get(
 	ctx context.Context,
-	gvk schema.GroupVersionKind,
+	key informerKey,
 	obj runtime.Object,
 ) (informer cache.SharedIndexInformer, reader client.Reader, err error) {
 	// Return the informer if it is found
@@ -96,14 +109,14 @@ func (im *InformerMap) Get(
 		cache.SharedIndexInformer, client.Reader, bool) {
 		im.informersMux.RLock()
 		defer im.informersMux.RUnlock()
-		entry, ok := im.informers[gvk]
+		entry, ok := im.informers[key]
 		return entry.Informer, entry.Reader, ok
 	}()
 
 	if !ok {
 		var err error
 		if informer, reader, err = im.addInformerToMap(
-			ctx, gvk, obj); err != nil {
+			ctx, key, obj); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -117,43 +130,62 @@ func (im *InformerMap) Get(
 	return
 }
 
-// Delete shuts down an informer for the given GVK, if one is registered.
+// Delete shuts down an informer for the given GVK and namespace, if one is registered.
 func (im *InformerMap) Delete(
 	ctx context.Context,
-	gvk schema.GroupVersionKind,
+	key informerKey,
 ) error {
 	im.informersMux.Lock()
 	defer im.informersMux.Unlock()
 
-	entry, ok := im.informers[gvk]
+	entry, ok := im.informers[key]
 	if !ok {
 		return nil
 	}
 
 	close(entry.StopCh)
-	delete(im.informers, gvk)
+	delete(im.informers, key)
 	return nil
 }
 
+// HasSynced returns true, when all registered informers have synced.
+func (im *InformerMap) HasSynced() bool {
+	im.informersMux.RLock()
+	defer im.informersMux.RUnlock()
+
+	for _, entry := range im.informers {
+		if !entry.Informer.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
 func (im *InformerMap) addInformerToMap(
-	ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object,
+	ctx context.Context, key informerKey, obj runtime.Object,
 ) (informer cache.SharedIndexInformer, reader client.Reader, err error) {
 	im.informersMux.Lock()
 	defer im.informersMux.Unlock()
 
 	// Ensure we are not creating multiple informers for the same type.
-	if entry, ok := im.informers[gvk]; ok {
+	if entry, ok := im.informers[key]; ok {
 		return entry.Informer, entry.Reader, nil
 	}
 
+	gvk := key.GroupVersionKind
 	// Create a new Informer and add it to the map.
-	lw, err := im.createListWatch(context.Background(), gvk)
+	lw, err := im.createListWatch(context.Background(), key)
 	if err != nil {
 		return nil, nil, err
 	}
 	ni := cache.NewSharedIndexInformer(lw, obj, resyncPeriod(im.resync)(), cache.Indexers{
 		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
 	})
+	if transform := im.stripFields(gvk).transformFunc(); transform != nil {
+		if err := ni.SetTransform(transform); err != nil {
+			return nil, nil, fmt.Errorf("setting transform to strip fields: %w", err)
+		}
+	}
 	for _, indexer := range im.indexers(gvk) {
 		if err := indexByField(ni, indexer.Field, indexer.Indexer); err != nil {
 			return nil, nil, fmt.Errorf(
@@ -175,7 +207,7 @@ func (im *InformerMap) addInformerToMap(
 		},
 		StopCh: make(chan struct{}, 1),
 	}
-	im.informers[gvk] = e
+	im.informers[key] = e
 	go e.Informer.Run(e.StopCh)
 
 	return e.Informer, e.Reader, nil
@@ -183,8 +215,9 @@ func (im *InformerMap) addInformerToMap(
 
 // newListWatch returns a new ListWatch object that can be used to create a SharedIndexInformer.
 func (im *InformerMap) createListWatch(
-	ctx context.Context, gvk schema.GroupVersionKind,
+	ctx context.Context, key informerKey,
 ) (*cache.ListWatch, error) {
+	gvk := key.GroupVersionKind
 	// Kubernetes APIs work against Resources, not GroupVersionKinds.  Map the
 	// groupVersionKind to the Resource API we will use.
 	mapping, err := im.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
@@ -192,7 +225,10 @@ func (im *InformerMap) createListWatch(
 		return nil, err
 	}
 
-	client := im.dynamicClient.Resource(mapping.Resource)
+	var client dynamic.ResourceInterface = im.dynamicClient.Resource(mapping.Resource)
+	if len(key.Namespace) > 0 {
+		client = im.dynamicClient.Resource(mapping.Resource).Namespace(key.Namespace)
+	}
 
 	return &cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
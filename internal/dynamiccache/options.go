@@ -10,6 +10,7 @@ import (
 var (
 	_ CacheOption = (*FieldIndexersByGVK)(nil)
 	_ CacheOption = (*SelectorsByGVK)(nil)
+	_ CacheOption = (*NamespacedWatches)(nil)
 )
 
 // FieldIndexers by GroupVersionKind.
@@ -41,6 +42,15 @@ func (s SelectorsByGVK) ApplyToCacheOptions(opts *CacheOptions) {
 // so that all informers will not send list requests simultaneously.
 type ResyncInterval time.Duration
 
+// Creates informers for namespaced objects scoped to the namespace of the watching owner,
+// instead of cluster-wide informers per GVK.
+// Owners that are cluster-scoped will still create cluster-wide informers.
+type NamespacedWatches bool
+
+func (nw NamespacedWatches) ApplyToCacheOptions(opts *CacheOptions) {
+	opts.NamespacedWatches = bool(nw)
+}
+
 // Default cache resunc interval, if not specified.
 const defaultResyncInterval = 10 * time.Hour
 
@@ -63,8 +73,12 @@ type CacheOptions struct {
 	Indexers FieldIndexersByGVK
 	// Selectors filter caches on the api server.
 	Selectors SelectorsByGVK
+	// StripFields drops fields from cached objects to reduce memory usage.
+	StripFields StripFieldsByGVK
 	// Time between full cache resyncs.
 	ResyncInterval time.Duration
+	// Scope informers of namespaced objects to the namespace of their owner.
+	NamespacedWatches bool
 }
 
 func (co *CacheOptions) Default() {
@@ -0,0 +1,75 @@
+package dynamiccache
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ CacheOption = (*StripFieldsByGVK)(nil)
+
+// Annotation written by `kubectl apply`, containing a full copy of the object.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// StripFields configures fields that are dropped from objects before they are stored in the cache.
+// Stripped fields are never visible to readers of the cache,
+// so they must not be used by any controller reading from it.
+type StripFields struct {
+	// Drops .metadata.managedFields.
+	ManagedFields bool
+	// Drops the kubectl last-applied-configuration annotation.
+	LastAppliedAnnotation bool
+	// Additional field paths to drop, e.g. []string{"status"}.
+	Paths [][]string
+}
+
+// StripFields by GroupVersionKind.
+// The empty GroupVersionKind configures the default for all GVKs not listed.
+type StripFieldsByGVK map[schema.GroupVersionKind]StripFields
+
+func (sf StripFieldsByGVK) ApplyToCacheOptions(opts *CacheOptions) {
+	opts.StripFields = sf
+}
+
+func (sf StripFieldsByGVK) forGVK(gvk schema.GroupVersionKind) StripFields {
+	if specific, found := sf[gvk]; found {
+		return specific
+	}
+	if defaultStripFields, found := sf[schema.GroupVersionKind{}]; found {
+		return defaultStripFields
+	}
+
+	return StripFields{}
+}
+
+// Returns a transform function for informers, removing the configured fields.
+// Returns nil, if nothing is configured to be stripped.
+func (sf StripFields) transformFunc() cache.TransformFunc {
+	if !sf.ManagedFields && !sf.LastAppliedAnnotation && len(sf.Paths) == 0 {
+		return nil
+	}
+
+	return func(in interface{}) (interface{}, error) {
+		// Deletion tombstones and other types are passed through.
+		obj, ok := in.(*unstructured.Unstructured)
+		if !ok {
+			return in, nil
+		}
+
+		if sf.ManagedFields {
+			obj.SetManagedFields(nil)
+		}
+		if sf.LastAppliedAnnotation {
+			if annotations := obj.GetAnnotations(); annotations != nil {
+				if _, ok := annotations[lastAppliedAnnotation]; ok {
+					delete(annotations, lastAppliedAnnotation)
+					obj.SetAnnotations(annotations)
+				}
+			}
+		}
+		for _, path := range sf.Paths {
+			unstructured.RemoveNestedField(obj.Object, path...)
+		}
+		return obj, nil
+	}
+}
@@ -0,0 +1,67 @@
+package dynamiccache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestStripFieldsByGVK_forGVK(t *testing.T) {
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+	sf := StripFieldsByGVK{
+		schema.GroupVersionKind{}: {ManagedFields: true},
+		secretGVK:                 {ManagedFields: true, LastAppliedAnnotation: true},
+	}
+
+	assert.Equal(t, StripFields{ManagedFields: true, LastAppliedAnnotation: true}, sf.forGVK(secretGVK))
+	assert.Equal(t, StripFields{ManagedFields: true}, sf.forGVK(schema.GroupVersionKind{Kind: "ConfigMap"}))
+	assert.Equal(t, StripFields{}, StripFieldsByGVK(nil).forGVK(secretGVK))
+}
+
+func TestStripFields_transformFunc(t *testing.T) {
+	assert.Nil(t, StripFields{}.transformFunc())
+
+	transform := StripFields{
+		ManagedFields:         true,
+		LastAppliedAnnotation: true,
+		Paths:                 [][]string{{"status"}},
+	}.transformFunc()
+	require.NotNil(t, transform)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "test",
+			"annotations": map[string]interface{}{
+				lastAppliedAnnotation: "{}",
+				"other":               "xxx",
+			},
+			"managedFields": []interface{}{
+				map[string]interface{}{"manager": "kubectl"},
+			},
+		},
+		"status": map[string]interface{}{
+			"large": "blob",
+		},
+	}}
+	out, err := transform(obj)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name": "test",
+			"annotations": map[string]interface{}{
+				"other": "xxx",
+			},
+		},
+	}, out.(*unstructured.Unstructured).Object)
+
+	// Tombstones are passed through.
+	tombstone := cache.DeletedFinalStateUnknown{Key: "test"}
+	out, err = transform(tombstone)
+	require.NoError(t, err)
+	assert.Equal(t, tombstone, out)
+}
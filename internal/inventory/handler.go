@@ -0,0 +1,105 @@
+package inventory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+// Inventory lists all ObjectSets and ClusterObjectSets that are not archived.
+type Inventory struct {
+	ObjectSets []ObjectSet `json:"objectSets"`
+}
+
+// ObjectSet summarizes the revision and health of a single ObjectSet or ClusterObjectSet.
+type ObjectSet struct {
+	Kind      string                            `json:"kind"`
+	Namespace string                            `json:"namespace,omitempty"`
+	Name      string                            `json:"name"`
+	Revision  int64                             `json:"revision"`
+	Phase     corev1alpha1.ObjectSetStatusPhase `json:"phase"`
+	Available bool                              `json:"available"`
+}
+
+// Handler serves the inventory as JSON,
+// so consumers need neither list permissions nor multiple requests.
+type Handler struct {
+	reader client.Reader
+	log    logr.Logger
+}
+
+func NewHandler(reader client.Reader, log logr.Logger) *Handler {
+	return &Handler{
+		reader: reader,
+		log:    log,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	inventory, err := h.collect(req)
+	if err != nil {
+		h.log.Error(err, "collecting inventory")
+		http.Error(w, "collecting inventory failed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(inventory); err != nil {
+		h.log.Error(err, "writing inventory")
+	}
+}
+
+func (h *Handler) collect(req *http.Request) (*Inventory, error) {
+	ctx := req.Context()
+	inventory := &Inventory{ObjectSets: []ObjectSet{}}
+
+	objectSets := &corev1alpha1.ObjectSetList{}
+	if err := h.reader.List(ctx, objectSets); err != nil {
+		return nil, fmt.Errorf("listing ObjectSets: %w", err)
+	}
+	for i := range objectSets.Items {
+		objectSet := &objectSets.Items[i]
+		inventory.add("ObjectSet", &objectSet.ObjectMeta,
+			objectSet.Status.Revision, objectSet.Status.Phase, objectSet.Status.Conditions)
+	}
+
+	clusterObjectSets := &corev1alpha1.ClusterObjectSetList{}
+	if err := h.reader.List(ctx, clusterObjectSets); err != nil {
+		return nil, fmt.Errorf("listing ClusterObjectSets: %w", err)
+	}
+	for i := range clusterObjectSets.Items {
+		objectSet := &clusterObjectSets.Items[i]
+		inventory.add("ClusterObjectSet", &objectSet.ObjectMeta,
+			objectSet.Status.Revision, objectSet.Status.Phase, objectSet.Status.Conditions)
+	}
+	return inventory, nil
+}
+
+func (i *Inventory) add(
+	kind string, obj *metav1.ObjectMeta, revision int64,
+	phase corev1alpha1.ObjectSetStatusPhase, conditions []metav1.Condition,
+) {
+	if phase == corev1alpha1.ObjectSetStatusPhaseArchived {
+		return
+	}
+	i.ObjectSets = append(i.ObjectSets, ObjectSet{
+		Kind:      kind,
+		Namespace: obj.Namespace,
+		Name:      obj.Name,
+		Revision:  revision,
+		Phase:     phase,
+		Available: meta.IsStatusConditionTrue(conditions, corev1alpha1.ObjectSetAvailable),
+	})
+}
@@ -0,0 +1,67 @@
+package inventory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+	"package-operator.run/package-operator/internal/testutil"
+)
+
+func TestHandler(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("List", mock.Anything, mock.AnythingOfType("*v1alpha1.ObjectSetList"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*corev1alpha1.ObjectSetList)
+			list.Items = []corev1alpha1.ObjectSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-rev2", Namespace: "test"},
+					Status: corev1alpha1.ObjectSetStatus{
+						Revision: 2,
+						Phase:    corev1alpha1.ObjectSetStatusPhaseAvailable,
+						Conditions: []metav1.Condition{
+							{Type: corev1alpha1.ObjectSetAvailable, Status: metav1.ConditionTrue},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-rev1", Namespace: "test"},
+					Status: corev1alpha1.ObjectSetStatus{
+						Revision: 1,
+						Phase:    corev1alpha1.ObjectSetStatusPhaseArchived,
+					},
+				},
+			}
+		}).
+		Return(nil)
+	c.On("List", mock.Anything, mock.AnythingOfType("*v1alpha1.ClusterObjectSetList"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*corev1alpha1.ClusterObjectSetList)
+			list.Items = []corev1alpha1.ClusterObjectSet{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster-rev1"},
+					Status: corev1alpha1.ClusterObjectSetStatus{
+						Revision: 1,
+						Phase:    corev1alpha1.ObjectSetStatusPhaseNotReady,
+					},
+				},
+			}
+		}).
+		Return(nil)
+
+	h := NewHandler(c, logr.Discard())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/inventory", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"objectSets":[
+		{"kind":"ObjectSet","namespace":"test","name":"test-rev2","revision":2,"phase":"Available","available":true},
+		{"kind":"ClusterObjectSet","name":"cluster-rev1","revision":1,"phase":"NotReady","available":false}
+	]}`, rec.Body.String())
+}
@@ -0,0 +1,67 @@
+// The package jsonpath evaluates JSON Path expressions against unstructured objects.
+// It wraps k8s.io/client-go/util/jsonpath, the JSON Path dialect of kubectl.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Parses a JSON Path expression.
+// Curly braces are optional for single expressions,
+// so ".status.version" and "{.status.version}" are equivalent.
+func Parse(path string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	p := jsonpath.New("").AllowMissingKeys(true)
+	if err := p.Parse(path); err != nil {
+		return nil, fmt.Errorf("parsing JSON Path %q: %w", path, err)
+	}
+	return p, nil
+}
+
+// Returns the value at the given JSON Path within obj.
+// Strings are returned as is, other values JSON encoded.
+// Multiple results are returned as JSON array.
+// Returns false, if the path does not resolve to any value,
+// including out of range indices and type mismatches.
+func Get(obj map[string]interface{}, path string) (value string, ok bool, err error) {
+	p, err := Parse(path)
+	if err != nil {
+		return "", false, err
+	}
+	results, err := p.FindResults(obj)
+	if err != nil {
+		// Evaluation only fails, if the object does not have the expected structure (yet).
+		return "", false, nil //nolint:nilerr
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, v.Interface())
+		}
+	}
+
+	var field interface{}
+	switch len(values) {
+	case 0:
+		return "", false, nil
+	case 1:
+		field = values[0]
+	default:
+		field = values
+	}
+	if s, isString := field.(string); isString {
+		return s, true, nil
+	}
+	j, err := json.Marshal(field)
+	if err != nil {
+		return "", false, err
+	}
+	return string(j), true, nil
+}
@@ -0,0 +1,49 @@
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"version":  "v1.2.3",
+			"replicas": int64(3),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+				map[string]interface{}{"type": "Progressing", "status": "False"},
+			},
+		},
+	}
+
+	tests := []struct {
+		path  string
+		value string
+		ok    bool
+	}{
+		{path: ".status.version", value: "v1.2.3", ok: true},
+		{path: "{.status.version}", value: "v1.2.3", ok: true},
+		{path: ".status.replicas", value: "3", ok: true},
+		{path: ".status.conditions[0].type", value: "Available", ok: true},
+		{path: `.status.conditions[?(@.type=="Available")].status`, value: "True", ok: true},
+		{path: ".status.conditions[*].type", value: `["Available","Progressing"]`, ok: true},
+		{path: ".status.missing"},
+		{path: ".status.conditions[5].type"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			value, ok, err := Get(obj, test.path)
+			require.NoError(t, err)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.value, value)
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	_, err := Parse(".status.conditions[?(@.type==")
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsPrefix = "package_operator_"
+
+// Recorder stores and exposes metrics about Package Operator reconciliation.
+type Recorder struct {
+	objectDriftDetected *prometheus.CounterVec
+	objectDriftReverted *prometheus.CounterVec
+	objectConflicts     *prometheus.CounterVec
+
+	objectSetUnavailableSince        *prometheus.GaugeVec
+	objectSetPreviousRevisionMissing *prometheus.GaugeVec
+}
+
+func NewRecorder() *Recorder {
+	objectLabels := []string{"group", "version", "kind", "owner_namespace", "owner_name"}
+
+	objectDriftDetected := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metricsPrefix + "object_drift_detected_total",
+			Help: "Number of times an object was found to be modified out-of-band.",
+		}, objectLabels)
+	objectDriftReverted := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metricsPrefix + "object_drift_reverted_total",
+			Help: "Number of times an out-of-band modification of an object was reverted.",
+		}, objectLabels)
+	objectConflicts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: metricsPrefix + "object_conflicts_total",
+			Help: "Number of times an object could not be adopted, by field manager of the object.",
+		}, append(objectLabels, "manager"))
+
+	objectSetLabels := []string{"namespace", "name"}
+
+	objectSetUnavailableSince := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metricsPrefix + "objectset_unavailable_since_timestamp_seconds",
+			Help: "Unix timestamp since when an ObjectSet is not Available. Only reported for unavailable ObjectSets.",
+		}, objectSetLabels)
+	objectSetPreviousRevisionMissing := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: metricsPrefix + "objectset_previous_revision_missing",
+			Help: "Reported as 1 while an ObjectSet references a previous revision that does not exist.",
+		}, objectSetLabels)
+
+	return &Recorder{
+		objectDriftDetected: objectDriftDetected,
+		objectDriftReverted: objectDriftReverted,
+		objectConflicts:     objectConflicts,
+
+		objectSetUnavailableSince:        objectSetUnavailableSince,
+		objectSetPreviousRevisionMissing: objectSetPreviousRevisionMissing,
+	}
+}
+
+// Register metrics into the controller-runtime metrics registry,
+// which is served by the manager's metrics endpoint.
+func (r *Recorder) Register() {
+	ctrlmetrics.Registry.MustRegister(
+		r.objectDriftDetected,
+		r.objectDriftReverted,
+		r.objectConflicts,
+		r.objectSetUnavailableSince,
+		r.objectSetPreviousRevisionMissing,
+	)
+}
+
+// Records that the given object differs from its desired state.
+func (r *Recorder) RecordObjectDriftDetected(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	r.objectDriftDetected.WithLabelValues(objectLabelValues(owner, gvk)...).Inc()
+}
+
+// Records that the given object was patched back into its desired state.
+func (r *Recorder) RecordObjectDriftReverted(
+	owner client.Object, gvk schema.GroupVersionKind,
+) {
+	r.objectDriftReverted.WithLabelValues(objectLabelValues(owner, gvk)...).Inc()
+}
+
+// Records that the given object could not be adopted,
+// once for every field manager of the object.
+func (r *Recorder) RecordObjectConflict(
+	owner client.Object, gvk schema.GroupVersionKind, manager string,
+) {
+	r.objectConflicts.WithLabelValues(
+		append(objectLabelValues(owner, gvk), manager)...).Inc()
+}
+
+// Records since when the given ObjectSet is not Available.
+// Passing nil removes the ObjectSet from the metric.
+func (r *Recorder) RecordObjectSetUnavailableSince(
+	objectSet client.Object, since *time.Time,
+) {
+	labels := objectSetLabelValues(objectSet)
+	if since == nil {
+		r.objectSetUnavailableSince.DeleteLabelValues(labels...)
+		return
+	}
+	r.objectSetUnavailableSince.WithLabelValues(labels...).Set(float64(since.Unix()))
+}
+
+// Records whether the given ObjectSet references a previous revision that does not exist.
+func (r *Recorder) RecordObjectSetPreviousRevisionMissing(
+	objectSet client.Object, missing bool,
+) {
+	labels := objectSetLabelValues(objectSet)
+	if !missing {
+		r.objectSetPreviousRevisionMissing.DeleteLabelValues(labels...)
+		return
+	}
+	r.objectSetPreviousRevisionMissing.WithLabelValues(labels...).Set(1)
+}
+
+func objectSetLabelValues(objectSet client.Object) []string {
+	return []string{objectSet.GetNamespace(), objectSet.GetName()}
+}
+
+func objectLabelValues(owner client.Object, gvk schema.GroupVersionKind) []string {
+	return []string{
+		gvk.Group, gvk.Version, gvk.Kind,
+		owner.GetNamespace(), owner.GetName(),
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
+)
+
+func TestRecorder_ObjectSetGauges(t *testing.T) {
+	r := NewRecorder()
+	objectSet := &corev1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-rev1", Namespace: "test"},
+	}
+
+	since := time.Unix(1700000000, 0)
+	r.RecordObjectSetUnavailableSince(objectSet, &since)
+	r.RecordObjectSetPreviousRevisionMissing(objectSet, true)
+	assert.Equal(t, float64(1700000000),
+		testutil.ToFloat64(r.objectSetUnavailableSince.WithLabelValues("test", "test-rev1")))
+	assert.Equal(t, 1, testutil.CollectAndCount(r.objectSetPreviousRevisionMissing))
+
+	r.RecordObjectSetUnavailableSince(objectSet, nil)
+	r.RecordObjectSetPreviousRevisionMissing(objectSet, false)
+	assert.Equal(t, 0, testutil.CollectAndCount(r.objectSetUnavailableSince))
+	assert.Equal(t, 0, testutil.CollectAndCount(r.objectSetPreviousRevisionMissing))
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var longestRunningProcessorMetric = ctrlmetrics.WorkQueueSubsystem + "_" +
+	ctrlmetrics.LongestRunningProcessorKey
+
+// WorkQueueStallChecker fails liveness checks,
+// when a controller has been processing a single work item for longer than the threshold.
+// A stalled worker will not recover on its own, so restarting the process is the best option.
+type WorkQueueStallChecker struct {
+	gatherer  prometheus.Gatherer
+	threshold time.Duration
+}
+
+// Creates a new WorkQueueStallChecker reading the workqueue metrics
+// that controller-runtime registers into its metrics registry.
+func NewWorkQueueStallChecker(threshold time.Duration) *WorkQueueStallChecker {
+	return &WorkQueueStallChecker{
+		gatherer:  ctrlmetrics.Registry,
+		threshold: threshold,
+	}
+}
+
+// Check implements healthz.Checker.
+func (c *WorkQueueStallChecker) Check(_ *http.Request) error {
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("gathering metrics: %w", err)
+	}
+
+	var stalled []string
+	for _, family := range families {
+		if family.GetName() != longestRunningProcessorMetric {
+			continue
+		}
+
+		for _, m := range family.GetMetric() {
+			seconds := m.GetGauge().GetValue()
+			if seconds <= c.threshold.Seconds() {
+				continue
+			}
+
+			var name string
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "name" {
+					name = label.GetValue()
+				}
+			}
+			stalled = append(stalled, fmt.Sprintf(
+				"%s (%s)", name, time.Duration(seconds*float64(time.Second)).Round(time.Second)))
+		}
+	}
+	if len(stalled) == 0 {
+		return nil
+	}
+
+	sort.Strings(stalled)
+	return fmt.Errorf("work queues stalled: %s", strings.Join(stalled, ", "))
+}
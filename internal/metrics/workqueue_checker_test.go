@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkQueueStallChecker(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: longestRunningProcessorMetric,
+	}, []string{"name"})
+	reg.MustRegister(gauge)
+
+	c := &WorkQueueStallChecker{
+		gatherer:  reg,
+		threshold: time.Minute,
+	}
+
+	gauge.WithLabelValues("objectset").Set(30)
+	assert.NoError(t, c.Check(nil))
+
+	gauge.WithLabelValues("objectsetphase").Set(120)
+	assert.EqualError(t, c.Check(nil), "work queues stalled: objectsetphase (2m0s)")
+}
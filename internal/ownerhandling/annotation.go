@@ -113,6 +113,16 @@ func (s *OwnerStrategyAnnotation) IsController(
 	return false
 }
 
+// Returns true, if the object is controlled by any owner.
+func (s *OwnerStrategyAnnotation) HasController(obj metav1.Object) bool {
+	for _, ownerRef := range s.getOwnerReferences(obj) {
+		if ownerRef.isController() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *OwnerStrategyAnnotation) RemoveOwner(owner, obj metav1.Object) {
 	ownerRefComp := s.ownerRefForCompare(owner)
 	ownerRefs := s.getOwnerReferences(obj)
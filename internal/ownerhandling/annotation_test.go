@@ -96,12 +96,14 @@ func TestOwnerStrategyAnnotation_ReleaseController(t *testing.T) {
 	if assert.Len(t, ownerRefs, 1) {
 		assert.NotNil(t, ownerRefs[0].Controller)
 	}
+	assert.True(t, s.HasController(obj))
 
 	s.ReleaseController(obj)
 	ownerRefs = s.getOwnerReferences(obj)
 	if assert.Len(t, ownerRefs, 1) {
 		assert.Nil(t, ownerRefs[0].Controller)
 	}
+	assert.False(t, s.HasController(obj))
 }
 
 func TestOwnerStrategyAnnotation_IndexOf(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 type ownerStrategy interface {
 	IsOwner(owner, obj metav1.Object) bool
 	IsController(owner, obj metav1.Object) bool
+	HasController(obj metav1.Object) bool
 	ReleaseController(obj metav1.Object)
 	RemoveOwner(owner, obj metav1.Object)
 	SetControllerReference(owner, obj metav1.Object) error
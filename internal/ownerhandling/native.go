@@ -48,6 +48,11 @@ func (s *OwnerStrategyNative) IsController(
 	return false
 }
 
+// Returns true, if the object is controlled by any owner.
+func (s *OwnerStrategyNative) HasController(obj metav1.Object) bool {
+	return metav1.GetControllerOf(obj) != nil
+}
+
 func (s *OwnerStrategyNative) RemoveOwner(owner, obj metav1.Object) {
 	ownerRefComp := s.ownerRefForCompare(owner)
 	ownerRefs := obj.GetOwnerReferences()
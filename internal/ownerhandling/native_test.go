@@ -143,10 +143,12 @@ func TestOwnerStrategyNative_ReleaseController(t *testing.T) {
 	if assert.Len(t, ownerRefs, 1) {
 		assert.NotNil(t, ownerRefs[0].Controller)
 	}
+	assert.True(t, s.HasController(obj))
 
 	s.ReleaseController(obj)
 	ownerRefs = obj.GetOwnerReferences()
 	if assert.Len(t, ownerRefs, 1) {
 		assert.Nil(t, ownerRefs[0].Controller)
 	}
+	assert.False(t, s.HasController(obj))
 }
@@ -6,6 +6,15 @@ var (
 	errObjectSetTemplatePhaseImmutable = errors.New("ObjectSetTemplatePhase is immutable")
 	errObjectSetTemplateSpecImmutable  = errors.New("ObjectSetTemplateSpec is immutable")
 	errPreviousImmutable               = errors.New(".spec.Previous is immutable")
+	errServiceAccountNameImmutable     = errors.New(".spec.serviceAccountName is immutable")
 	errRevisionImmutable               = errors.New(".spec.Revision is immutable")
 	errAvailabilityProbesImmutable     = errors.New(".spec.AvailabilityProbes is immutable")
+	errPreviousNameEmpty               = errors.New("name must not be empty")
+	errPreviousDuplicate               = errors.New("duplicate reference")
+	errPreviousSelfReference           = errors.New("must not reference the object itself")
+	errProbeSelectsNoObject            = errors.New("selector matches no object in any phase")
+	errTargetClusterWithoutClass       = errors.New("targetCluster requires a class")
+	errKubeconfigSecretNamespace       = errors.New("kubeconfig Secret must be in the namespace of the object")
+	errKubeconfigSecretNoNamespace     = errors.New("kubeconfig Secret requires a namespace")
+	errServiceAccountNameWithClass     = errors.New("serviceAccountName is not supported for phases with a class")
 )
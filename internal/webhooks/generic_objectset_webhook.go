@@ -2,16 +2,24 @@ package webhooks
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/api/admission/v1"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+
+	"package-operator.run/package-operator/internal/jsonpath"
 )
 
 type objectSets interface {
@@ -69,6 +77,8 @@ func (wh *GenericObjectSetWebhookHandler[T]) Handle(
 	}
 
 	switch req.Operation {
+	case v1.Operation(admissionv1beta1.Create):
+		return wh.validateCreate(obj)
 	case v1.Operation(admissionv1beta1.Update):
 		oldObj := wh.newObjectSet()
 		if err := wh.decoder.DecodeRaw(
@@ -86,6 +96,29 @@ func (wh *GenericObjectSetWebhookHandler[T]) InjectDecoder(d *admission.Decoder)
 	return nil
 }
 
+func (wh *GenericObjectSetWebhookHandler[T]) validateCreate(obj *T) admission.Response {
+	fields := objectSetImmutableFields(obj)
+	if err := validatePrevious(
+		any(obj).(client.Object).GetName(), fields.Previous); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := validateAvailabilityProbes(fields.ObjectSetTemplateSpec); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := validateStatusCollection(fields.StatusCollection); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := validateTargetClusters(
+		any(obj).(client.Object).GetNamespace(), fields.Phases); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if err := validateServiceAccountName(fields.ServiceAccountName, fields.Phases); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("operation allowed").
+		WithWarnings(availabilityProbeWarnings(fields.ObjectSetTemplateSpec)...)
+}
+
 func (wh *GenericObjectSetWebhookHandler[T]) validateUpdate(
 	obj, oldObj *T) admission.Response {
 	if err := validateGenericObjectSetImmutability(obj, oldObj); err != nil {
@@ -108,18 +141,24 @@ func validateGenericObjectSetImmutability[T objectSets](obj, oldObj *T) error {
 		newFields.Previous, oldFields.Previous) {
 		return errPreviousImmutable
 	}
+
+	if newFields.ServiceAccountName != oldFields.ServiceAccountName {
+		return errServiceAccountNameImmutable
+	}
 	return nil
 }
 
 type genericImmutableFields struct {
 	Previous                           []corev1alpha1.PreviousRevisionReference `json:"previous,omitempty"`
+	ServiceAccountName                 string                                   `json:"serviceAccountName,omitempty"`
 	corev1alpha1.ObjectSetTemplateSpec `json:",inline"`
 }
 
 func objectSetImmutableFields[T objectSets](obj *T) genericImmutableFields {
 	var (
-		previous []corev1alpha1.PreviousRevisionReference
-		template *corev1alpha1.ObjectSetTemplateSpec
+		previous           []corev1alpha1.PreviousRevisionReference
+		serviceAccountName string
+		template           *corev1alpha1.ObjectSetTemplateSpec
 	)
 
 	switch v := any(obj).(type) {
@@ -128,11 +167,227 @@ func objectSetImmutableFields[T objectSets](obj *T) genericImmutableFields {
 		template = &v.Spec.ObjectSetTemplateSpec
 	case *corev1alpha1.ObjectSet:
 		previous = v.Spec.Previous
+		serviceAccountName = v.Spec.ServiceAccountName
 		template = &v.Spec.ObjectSetTemplateSpec
 	}
 
 	return genericImmutableFields{
 		Previous:              previous,
+		ServiceAccountName:    serviceAccountName,
 		ObjectSetTemplateSpec: *template,
 	}
 }
+
+// Ensures previous revision references are well-formed.
+func validatePrevious(
+	name string, previous []corev1alpha1.PreviousRevisionReference,
+) error {
+	seen := map[string]struct{}{}
+	for i, prev := range previous {
+		switch _, duplicate := seen[prev.Name]; {
+		case len(prev.Name) == 0:
+			return fmt.Errorf(".spec.previous[%d]: %w", i, errPreviousNameEmpty)
+		case duplicate:
+			return fmt.Errorf(".spec.previous[%d] %q: %w", i, prev.Name, errPreviousDuplicate)
+		case prev.Name == name:
+			return fmt.Errorf(".spec.previous[%d] %q: %w", i, prev.Name, errPreviousSelfReference)
+		}
+		seen[prev.Name] = struct{}{}
+	}
+	return nil
+}
+
+// Ensures every availability probe selects at least one object of the template,
+// probes selecting nothing are most likely misconfigured and would never fail.
+func validateAvailabilityProbes(template corev1alpha1.ObjectSetTemplateSpec) error {
+	if len(template.AvailabilityProbes) == 0 {
+		return nil
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, phase := range template.Phases {
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			// Warning!
+			// This MUST absolutely use sigs.k8s.io/yaml
+			// Any other yaml parser, might yield unexpected results.
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				return fmt.Errorf("phase %q: converting RawExtension into unstructured: %w", phase.Name, err)
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	for i, probe := range template.AvailabilityProbes {
+		matches, err := probeSelectorMatchesAny(probe.Selector, objects)
+		if err != nil {
+			return fmt.Errorf(".spec.availabilityProbes[%d].selector: %w", i, err)
+		}
+		if !matches {
+			return fmt.Errorf(".spec.availabilityProbes[%d]: %w", i, errProbeSelectsNoObject)
+		}
+	}
+	return nil
+}
+
+var (
+	workloadGroupKinds = map[schema.GroupKind]struct{}{
+		{Group: "apps", Kind: "Deployment"}:  {},
+		{Group: "apps", Kind: "StatefulSet"}: {},
+		{Group: "apps", Kind: "DaemonSet"}:   {},
+	}
+	crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+)
+
+// Warns about objects that are likely to be reported Available too early:
+// workloads without any availability probe selecting them
+// and phases of only CustomResourceDefinitions, without a probe for the Established condition.
+// Following phases would start while their Pods or CRDs are not ready yet.
+func availabilityProbeWarnings(template corev1alpha1.ObjectSetTemplateSpec) []string {
+	var warnings []string
+	for i, phase := range template.Phases {
+		var (
+			onlyCRDs          = len(phase.Objects) > 0
+			crdsEstablished   = true
+			unprobedWorkloads []string
+		)
+		for _, phaseObject := range phase.Objects {
+			obj := &unstructured.Unstructured{}
+			// Warning!
+			// This MUST absolutely use sigs.k8s.io/yaml
+			// Any other yaml parser, might yield unexpected results.
+			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
+				// Malformed objects are rejected when reconciling.
+				onlyCRDs = false
+				continue
+			}
+			gk := obj.GroupVersionKind().GroupKind()
+			if _, ok := workloadGroupKinds[gk]; ok &&
+				!anyProbeSelects(template.AvailabilityProbes, obj, nil) {
+				unprobedWorkloads = append(unprobedWorkloads, gk.Kind+" "+obj.GetName())
+			}
+			if gk != crdGroupKind {
+				onlyCRDs = false
+				continue
+			}
+			if !anyProbeSelects(template.AvailabilityProbes, obj, isEstablishedProbe) {
+				crdsEstablished = false
+			}
+		}
+
+		for _, workload := range unprobedWorkloads {
+			warnings = append(warnings, fmt.Sprintf(
+				".spec.phases[%d]: %s is not selected by any availability probe", i, workload))
+		}
+		if onlyCRDs && !crdsEstablished {
+			warnings = append(warnings, fmt.Sprintf(
+				".spec.phases[%d]: phase only contains CustomResourceDefinitions, "+
+					"but not all of them are probed for the Established condition", i))
+		}
+	}
+	return warnings
+}
+
+// Returns true if any of the given probes selects the object
+// and, if check is not nil, contains a matching probe.
+func anyProbeSelects(
+	probes []corev1alpha1.ObjectSetProbe, obj *unstructured.Unstructured,
+	check func(probe corev1alpha1.Probe) bool,
+) bool {
+	for _, probe := range probes {
+		matches, err := probeSelectorMatchesAny(probe.Selector, []*unstructured.Unstructured{obj})
+		if err != nil || !matches {
+			continue
+		}
+		if check == nil {
+			return true
+		}
+		for _, p := range probe.Probes {
+			if check(p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isEstablishedProbe(probe corev1alpha1.Probe) bool {
+	return probe.Condition != nil &&
+		probe.Condition.Type == "Established" &&
+		probe.Condition.Status == string(metav1.ConditionTrue)
+}
+
+// Ensures all status collection field paths are valid JSON Path expressions,
+// so they don't silently resolve to nothing.
+func validateStatusCollection(statusCollection []corev1alpha1.ObjectSetStatusCollection) error {
+	for i, sc := range statusCollection {
+		if _, err := jsonpath.Parse(sc.FieldPath); err != nil {
+			return fmt.Errorf(".spec.statusCollection[%d].fieldPath: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Ensures target clusters of phases can be resolved,
+// only the controller of a class connects to target clusters
+// and namespaced objects may only reference Secrets in their own namespace.
+func validateTargetClusters(
+	namespace string, phases []corev1alpha1.ObjectSetTemplatePhase,
+) error {
+	for i, phase := range phases {
+		if phase.TargetCluster == nil {
+			continue
+		}
+		ref := phase.TargetCluster.KubeconfigSecretRef
+		switch {
+		case len(phase.Class) == 0:
+			return fmt.Errorf(".spec.phases[%d].targetCluster: %w", i, errTargetClusterWithoutClass)
+		case len(namespace) > 0 && len(ref.Namespace) > 0 && ref.Namespace != namespace:
+			return fmt.Errorf(".spec.phases[%d].targetCluster.kubeconfigSecretRef: %w", i, errKubeconfigSecretNamespace)
+		case len(namespace) == 0 && len(ref.Namespace) == 0:
+			return fmt.Errorf(".spec.phases[%d].targetCluster.kubeconfigSecretRef: %w", i, errKubeconfigSecretNoNamespace)
+		}
+	}
+	return nil
+}
+
+// Ensures a ServiceAccount is only set, when no phase is delegated to the controller of a class.
+// Those controllers manage objects with their own identity and would bypass impersonation.
+func validateServiceAccountName(
+	serviceAccountName string, phases []corev1alpha1.ObjectSetTemplatePhase,
+) error {
+	if len(serviceAccountName) == 0 {
+		return nil
+	}
+	for i, phase := range phases {
+		if len(phase.Class) > 0 {
+			return fmt.Errorf(".spec.phases[%d].class: %w", i, errServiceAccountNameWithClass)
+		}
+	}
+	return nil
+}
+
+func probeSelectorMatchesAny(
+	selector corev1alpha1.ProbeSelector, objects []*unstructured.Unstructured,
+) (bool, error) {
+	labelSelector := labels.Everything()
+	if selector.Selector != nil {
+		s, err := metav1.LabelSelectorAsSelector(selector.Selector)
+		if err != nil {
+			return false, err
+		}
+		labelSelector = s
+	}
+
+	for _, obj := range objects {
+		gk := obj.GroupVersionKind().GroupKind()
+		if selector.Kind != nil &&
+			(gk.Group != selector.Kind.Group || gk.Kind != selector.Kind.Kind) {
+			continue
+		}
+		if labelSelector.Matches(labels.Set(obj.GetLabels())) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
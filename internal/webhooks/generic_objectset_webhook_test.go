@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	corev1alpha1 "package-operator.run/apis/core/v1alpha1"
 )
@@ -32,4 +34,213 @@ func TestValidateUpdate_ObjectSet(t *testing.T) {
 		assert.False(t, r.Allowed)
 		assert.Equal(t, string(r.Result.Reason), errObjectSetTemplateSpecImmutable.Error())
 	})
+
+	t.Run("serviceAccountName immutable", func(t *testing.T) {
+		oldObj := wh.newObjectSet()
+		obj := wh.newObjectSet()
+		obj.Spec.ServiceAccountName = "tenant"
+		r := wh.validateUpdate(obj, oldObj)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, string(r.Result.Reason), errServiceAccountNameImmutable.Error())
+	})
+}
+
+func TestValidateCreate_ObjectSet(t *testing.T) {
+	wh := new(GenericObjectSetWebhookHandler[corev1alpha1.ObjectSet])
+
+	newObjectSet := func() *corev1alpha1.ObjectSet {
+		obj := wh.newObjectSet()
+		obj.Name = "test-2"
+		obj.Spec.Phases = []corev1alpha1.ObjectSetTemplatePhase{
+			{
+				Name: "deploy",
+				Objects: []corev1alpha1.ObjectSetObject{
+					{
+						Object: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test","labels":{"app":"test"}}}`),
+						},
+					},
+				},
+			},
+		}
+		return obj
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.Previous = []corev1alpha1.PreviousRevisionReference{{Name: "test-1"}}
+		obj.Spec.AvailabilityProbes = []corev1alpha1.ObjectSetProbe{
+			{
+				Selector: corev1alpha1.ProbeSelector{
+					Kind: &corev1alpha1.PackageProbeKindSpec{Group: "apps", Kind: "Deployment"},
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "test"},
+					},
+				},
+			},
+		}
+		r := wh.validateCreate(obj)
+		assert.True(t, r.Allowed)
+		assert.Empty(t, r.Warnings)
+	})
+
+	t.Run("previous self reference", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.Previous = []corev1alpha1.PreviousRevisionReference{{Name: "test-2"}}
+		r := wh.validateCreate(obj)
+		assert.False(t, r.Allowed)
+		assert.Contains(t, string(r.Result.Reason), errPreviousSelfReference.Error())
+	})
+
+	t.Run("previous duplicate", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.Previous = []corev1alpha1.PreviousRevisionReference{{Name: "test-1"}, {Name: "test-1"}}
+		r := wh.validateCreate(obj)
+		assert.False(t, r.Allowed)
+		assert.Contains(t, string(r.Result.Reason), errPreviousDuplicate.Error())
+	})
+
+	t.Run("probe selects nothing", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.AvailabilityProbes = []corev1alpha1.ObjectSetProbe{
+			{
+				Selector: corev1alpha1.ProbeSelector{
+					Kind: &corev1alpha1.PackageProbeKindSpec{Group: "apps", Kind: "StatefulSet"},
+				},
+			},
+		}
+		r := wh.validateCreate(obj)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, ".spec.availabilityProbes[0]: "+errProbeSelectsNoObject.Error(), string(r.Result.Reason))
+	})
+
+	t.Run("invalid status collection field path", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.StatusCollection = []corev1alpha1.ObjectSetStatusCollection{
+			{
+				Name:      "available",
+				FieldPath: `.status.conditions[?(@.type=="Available")].status`,
+				ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+					Group: "apps", Kind: "Deployment", Name: "test",
+				},
+			},
+			{
+				Name:      "broken",
+				FieldPath: ".status.conditions[0",
+				ObjectRef: corev1alpha1.StatusCollectionObjectReference{
+					Group: "apps", Kind: "Deployment", Name: "test",
+				},
+			},
+		}
+		r := wh.validateCreate(obj)
+		assert.False(t, r.Allowed)
+		assert.Contains(t, string(r.Result.Reason), ".spec.statusCollection[1].fieldPath")
+	})
+
+	t.Run("target cluster", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			namespace string
+			class     string
+			ref       corev1alpha1.KubeconfigSecretReference
+			err       error
+		}{
+			{
+				name: "valid", namespace: "test", class: "remote",
+				ref: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+			},
+			{
+				name: "without class", namespace: "test",
+				ref: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+				err: errTargetClusterWithoutClass,
+			},
+			{
+				name: "other namespace", namespace: "test", class: "remote",
+				ref: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig", Namespace: "other"},
+				err: errKubeconfigSecretNamespace,
+			},
+			{
+				name: "cluster-scoped without namespace", class: "remote",
+				ref: corev1alpha1.KubeconfigSecretReference{Name: "kubeconfig"},
+				err: errKubeconfigSecretNoNamespace,
+			},
+		}
+		for _, test := range tests {
+			test := test
+			t.Run(test.name, func(t *testing.T) {
+				obj := newObjectSet()
+				obj.Namespace = test.namespace
+				obj.Spec.Phases[0].Class = test.class
+				obj.Spec.Phases[0].TargetCluster = &corev1alpha1.ObjectSetPhaseTargetCluster{
+					KubeconfigSecretRef: test.ref,
+				}
+				r := wh.validateCreate(obj)
+				if test.err == nil {
+					assert.True(t, r.Allowed)
+					return
+				}
+				assert.False(t, r.Allowed)
+				assert.Contains(t, string(r.Result.Reason), test.err.Error())
+			})
+		}
+	})
+
+	t.Run("serviceAccountName with class phase", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.ServiceAccountName = "deployer"
+		r := wh.validateCreate(obj)
+		assert.True(t, r.Allowed)
+
+		obj.Spec.Phases[0].Class = "remote"
+		r = wh.validateCreate(obj)
+		assert.False(t, r.Allowed)
+		assert.Equal(t, ".spec.phases[0].class: "+errServiceAccountNameWithClass.Error(), string(r.Result.Reason))
+	})
+
+	t.Run("warns about workloads without probes", func(t *testing.T) {
+		obj := newObjectSet()
+		r := wh.validateCreate(obj)
+		assert.True(t, r.Allowed)
+		assert.Equal(t, []string{
+			".spec.phases[0]: Deployment test is not selected by any availability probe",
+		}, r.Warnings)
+	})
+
+	t.Run("warns about CRD phases without Established probe", func(t *testing.T) {
+		obj := newObjectSet()
+		obj.Spec.Phases = []corev1alpha1.ObjectSetTemplatePhase{
+			{
+				Name: "crds",
+				Objects: []corev1alpha1.ObjectSetObject{
+					{
+						Object: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"tests.example.com"}}`),
+						},
+					},
+				},
+			},
+		}
+		r := wh.validateCreate(obj)
+		assert.True(t, r.Allowed)
+		assert.Equal(t, []string{
+			".spec.phases[0]: phase only contains CustomResourceDefinitions, " +
+				"but not all of them are probed for the Established condition",
+		}, r.Warnings)
+
+		obj.Spec.AvailabilityProbes = []corev1alpha1.ObjectSetProbe{
+			{
+				Selector: corev1alpha1.ProbeSelector{
+					Kind: &corev1alpha1.PackageProbeKindSpec{
+						Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition",
+					},
+				},
+				Probes: []corev1alpha1.Probe{
+					{Condition: &corev1alpha1.ProbeConditionSpec{Type: "Established", Status: "True"}},
+				},
+			},
+		}
+		r = wh.validateCreate(obj)
+		assert.True(t, r.Allowed)
+		assert.Empty(t, r.Warnings)
+	})
 }
@@ -123,6 +123,7 @@ func (Build) Images() {
 	mg.Deps(
 		mg.F(Builder.Image, "package-operator-manager"),
 		mg.F(Builder.Image, "package-operator-webhook"),
+		mg.F(Builder.Image, "remote-phase-manager"),
 	)
 }
 
@@ -136,6 +137,7 @@ func (Build) PushImages() {
 	mg.Deps(
 		mg.F(Builder.Push, "package-operator-manager"),
 		mg.F(Builder.Push, "package-operator-webhook"),
+		mg.F(Builder.Push, "remote-phase-manager"),
 	)
 }
 